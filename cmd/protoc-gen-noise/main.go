@@ -0,0 +1,243 @@
+// protoc-gen-noise is a protoc plugin generating typed clients and server registration
+// helpers for services declared in .proto files, on top of noise's RPC layer.
+//
+// For every service, it generates a <Service>Client wrapping a *network.PeerClient with one
+// method per RPC, a <Service>Server interface, and a Register<Service>Server function which
+// registers an implementation of said interface onto an *rpc.Service.
+//
+// Install with `go install ./cmd/protoc-gen-noise`, after which `go generate` picks it up.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/gogo/protobuf/protoc-gen-gogo/generator"
+	plugin "github.com/gogo/protobuf/protoc-gen-gogo/plugin"
+	"github.com/pkg/errors"
+)
+
+func main() {
+	input, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fail(err)
+	}
+
+	req := new(plugin.CodeGeneratorRequest)
+	if err := proto.Unmarshal(input, req); err != nil {
+		fail(err)
+	}
+
+	res := generate(req)
+
+	output, err := proto.Marshal(res)
+	if err != nil {
+		fail(err)
+	}
+
+	if _, err := os.Stdout.Write(output); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "protoc-gen-noise: %+v\n", err)
+	os.Exit(1)
+}
+
+// generate generates a file of typed stubs for every requested .proto file declaring services.
+func generate(req *plugin.CodeGeneratorRequest) *plugin.CodeGeneratorResponse {
+	res := new(plugin.CodeGeneratorResponse)
+
+	files := make(map[string]*descriptor.FileDescriptorProto)
+	for _, file := range req.ProtoFile {
+		files[file.GetName()] = file
+	}
+
+	for _, name := range req.FileToGenerate {
+		file, exists := files[name]
+		if !exists {
+			res.Error = proto.String(fmt.Sprintf("file %s to generate was not provided", name))
+			return res
+		}
+
+		if len(file.Service) == 0 {
+			continue
+		}
+
+		content, err := generateFile(file)
+		if err != nil {
+			res.Error = proto.String(fmt.Sprintf("%s: %v", name, err))
+			return res
+		}
+
+		res.File = append(res.File, &plugin.CodeGeneratorResponse_File{
+			Name:    proto.String(strings.TrimSuffix(name, ".proto") + ".noise.go"),
+			Content: proto.String(content),
+		})
+	}
+
+	return res
+}
+
+type serviceData struct {
+	Name    string
+	Methods []methodData
+}
+
+type methodData struct {
+	Name   string
+	Input  string
+	Output string
+}
+
+type fileData struct {
+	Source   string
+	Package  string
+	Services []serviceData
+}
+
+// generateFile generates typed stubs for all services declared in a file.
+func generateFile(file *descriptor.FileDescriptorProto) (string, error) {
+	data := fileData{
+		Source:  file.GetName(),
+		Package: goPackageName(file),
+	}
+
+	for _, service := range file.Service {
+		s := serviceData{Name: generator.CamelCase(service.GetName())}
+
+		for _, method := range service.Method {
+			if method.GetClientStreaming() || method.GetServerStreaming() {
+				return "", errors.Errorf("streaming method %s.%s is not supported", service.GetName(), method.GetName())
+			}
+
+			input, err := goTypeName(file, method.GetInputType())
+			if err != nil {
+				return "", err
+			}
+
+			output, err := goTypeName(file, method.GetOutputType())
+			if err != nil {
+				return "", err
+			}
+
+			s.Methods = append(s.Methods, methodData{
+				Name:   generator.CamelCase(method.GetName()),
+				Input:  input,
+				Output: output,
+			})
+		}
+
+		data.Services = append(data.Services, s)
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to format generated code")
+	}
+
+	return string(formatted), nil
+}
+
+// goPackageName returns the name of the Go package generated for a file, following protoc-gen-gogo.
+func goPackageName(file *descriptor.FileDescriptorProto) string {
+	if pkg := file.GetOptions().GetGoPackage(); pkg != "" {
+		if i := strings.LastIndex(pkg, ";"); i >= 0 {
+			return pkg[i+1:]
+		}
+		return path.Base(pkg)
+	}
+
+	if pkg := file.GetPackage(); pkg != "" {
+		return strings.Replace(pkg, ".", "_", -1)
+	}
+
+	return strings.Replace(strings.TrimSuffix(path.Base(file.GetName()), ".proto"), ".", "_", -1)
+}
+
+// goTypeName returns the Go type name of a fully-qualified message name. Only messages declared
+// in the very same package as the service are supported.
+func goTypeName(file *descriptor.FileDescriptorProto, name string) (string, error) {
+	prefix := "."
+	if pkg := file.GetPackage(); pkg != "" {
+		prefix = "." + pkg + "."
+	}
+
+	if !strings.HasPrefix(name, prefix) {
+		return "", errors.Errorf("message %s is not declared in package %s", name, file.GetPackage())
+	}
+
+	return generator.CamelCaseSlice(strings.Split(strings.TrimPrefix(name, prefix), ".")), nil
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by protoc-gen-noise. DO NOT EDIT.
+// source: {{.Source}}
+
+package {{.Package}}
+
+import (
+	context "context"
+	fmt "fmt"
+
+	network "github.com/perlin-network/noise/network"
+	rpc "github.com/perlin-network/noise/network/rpc"
+)
+
+var _ = fmt.Errorf
+{{range $service := .Services}}
+// {{.Name}}Client is a typed client of the {{.Name}} service hosted by a peer.
+type {{.Name}}Client struct {
+	client *network.PeerClient
+}
+
+// New{{.Name}}Client returns a typed client of the {{.Name}} service hosted by a peer.
+func New{{.Name}}Client(client *network.PeerClient) *{{.Name}}Client {
+	return &{{.Name}}Client{client: client}
+}
+{{range .Methods}}
+// {{.Name}} sends a {{.Input}} to the peer and waits for its {{.Output}}.
+func (c *{{$service.Name}}Client) {{.Name}}(ctx context.Context, req *{{.Input}}) (*{{.Output}}, error) {
+	res, err := c.client.RequestContext(ctx, &rpc.Request{Message: req})
+	if err != nil {
+		return nil, err
+	}
+
+	reply, ok := res.(*{{.Output}})
+	if !ok {
+		return nil, fmt.Errorf("{{$service.Name}}.{{.Name}}: expected reply of type *{{.Output}}, got %T", res)
+	}
+
+	return reply, nil
+}
+{{end}}
+// {{.Name}}Server is the server API of the {{.Name}} service.
+type {{.Name}}Server interface {
+{{- range .Methods}}
+	{{.Name}}(context.Context, *{{.Input}}) (*{{.Output}}, error)
+{{- end}}
+}
+
+// Register{{.Name}}Server registers the handlers of the {{.Name}} service onto an RPC service.
+func Register{{.Name}}Server(s *rpc.Service, srv {{.Name}}Server) error {
+{{- range .Methods}}
+	if err := s.Handle(srv.{{.Name}}); err != nil {
+		return err
+	}
+{{- end}}
+	return nil
+}
+{{end}}`))
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	plugin "github.com/gogo/protobuf/protoc-gen-gogo/plugin"
+)
+
+func testFile(streaming bool) *descriptor.FileDescriptorProto {
+	return &descriptor.FileDescriptorProto{
+		Name:    proto.String("echo/echo.proto"),
+		Package: proto.String("echo"),
+		Service: []*descriptor.ServiceDescriptorProto{
+			{
+				Name: proto.String("echo_service"),
+				Method: []*descriptor.MethodDescriptorProto{
+					{
+						Name:            proto.String("echo"),
+						InputType:       proto.String(".echo.EchoRequest"),
+						OutputType:      proto.String(".echo.EchoResponse"),
+						ServerStreaming: proto.Bool(streaming),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	res := generate(&plugin.CodeGeneratorRequest{
+		FileToGenerate: []string{"echo/echo.proto"},
+		ProtoFile:      []*descriptor.FileDescriptorProto{testFile(false)},
+	})
+
+	if res.Error != nil {
+		t.Fatal(res.GetError())
+	}
+	if len(res.File) != 1 || res.File[0].GetName() != "echo/echo.noise.go" {
+		t.Fatalf("expected echo/echo.noise.go to be generated, got %v", res.File)
+	}
+
+	content := res.File[0].GetContent()
+
+	for _, expected := range []string{
+		"package echo",
+		"func NewEchoServiceClient(client *network.PeerClient) *EchoServiceClient",
+		"func (c *EchoServiceClient) Echo(ctx context.Context, req *EchoRequest) (*EchoResponse, error)",
+		"Echo(context.Context, *EchoRequest) (*EchoResponse, error)",
+		"func RegisterEchoServiceServer(s *rpc.Service, srv EchoServiceServer) error",
+	} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("expected generated code to contain %q, got:\n%s", expected, content)
+		}
+	}
+}
+
+func TestGenerateStreaming(t *testing.T) {
+	t.Parallel()
+
+	res := generate(&plugin.CodeGeneratorRequest{
+		FileToGenerate: []string{"echo/echo.proto"},
+		ProtoFile:      []*descriptor.FileDescriptorProto{testFile(true)},
+	})
+
+	if res.Error == nil {
+		t.Error("expected streaming methods to be rejected")
+	}
+}
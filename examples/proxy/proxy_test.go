@@ -106,7 +106,7 @@ func ExampleProxyPlugin() {
 	var plugins []*ProxyPlugin
 
 	for i := 0; i < numNodes; i++ {
-		addr := fmt.Sprintf("tcp://%s:%d", host, startPort+i)
+		addr := fmt.Sprintf("memory://%s:%d", host, startPort+i)
 		ids[addr] = i
 
 		builder := network.NewBuilder()
@@ -5,23 +5,13 @@ import (
 	"io"
 	"net"
 	"strings"
-	"time"
 
 	"github.com/golang/glog"
 	"github.com/perlin-network/noise/crypto/ed25519"
 	"github.com/perlin-network/noise/network"
 	"github.com/perlin-network/noise/network/discovery"
-	"github.com/xtaci/smux"
 )
 
-func muxStreamConfig() *smux.Config {
-	config := smux.DefaultConfig()
-	config.KeepAliveTimeout = 30 * time.Second
-	config.KeepAliveInterval = 5 * time.Second
-
-	return config
-}
-
 func proxy(a, b io.ReadWriter) {
 	ch1 := make(chan struct{})
 	ch2 := make(chan struct{})
@@ -45,25 +35,19 @@ type ExampleServerPlugin struct {
 	remoteAddress string
 }
 
-func (state *ExampleServerPlugin) PeerConnect(client *network.PeerClient) {
-	glog.Infof("New connection from %s.", client.Address)
-
-	go state.handleClient(client)
+func (state *ExampleServerPlugin) Startup(n *network.Network) {
+	go state.acceptStreams(n)
 }
 
-func (state *ExampleServerPlugin) handleClient(client *network.PeerClient) {
-	session, err := smux.Server(client, muxStreamConfig())
-	if err != nil {
-		glog.Fatal(err)
-	}
+func (state *ExampleServerPlugin) acceptStreams(n *network.Network) {
 	for {
-		stream, err := session.AcceptStream()
+		stream, err := n.AcceptStream()
 		if err != nil {
 			glog.Error(err)
 			break
 		}
 
-		glog.Infof("New incoming stream from %s.", client.Address)
+		glog.Infof("New incoming stream from %s.", stream.Client().Address)
 
 		go func() {
 			defer stream.Close()
@@ -80,6 +64,10 @@ func (state *ExampleServerPlugin) handleClient(client *network.PeerClient) {
 	}
 }
 
+func (state *ExampleServerPlugin) PeerConnect(client *network.PeerClient) {
+	glog.Infof("New connection from %s.", client.Address)
+}
+
 func (state *ExampleServerPlugin) PeerDisconnect(client *network.PeerClient) {
 	glog.Infof("Lost connection with %s.", client.Address)
 }
@@ -96,11 +84,6 @@ func (state *ProxyServerPlugin) PeerConnect(client *network.PeerClient) {
 }
 
 func (state *ProxyServerPlugin) startProxying(client *network.PeerClient) {
-	session, err := smux.Client(client, muxStreamConfig())
-	if err != nil {
-		glog.Fatal(err)
-	}
-
 	// Open proxy server.
 	listener, err := net.Listen("tcp", state.listenAddress)
 	if err != nil {
@@ -119,7 +102,7 @@ func (state *ProxyServerPlugin) startProxying(client *network.PeerClient) {
 		go func() {
 			defer conn.Close()
 
-			remote, err := session.OpenStream()
+			remote, err := client.OpenStream()
 			if err != nil {
 				glog.Error(err)
 				return
@@ -1,23 +1,32 @@
 module github.com/perlin-network/noise
 
+go 1.17
+
 require (
-	github.com/davecgh/go-spew v1.1.0 // indirect
 	github.com/fd/go-nat v1.0.0
 	github.com/gogo/protobuf v1.1.1
 	github.com/golang/glog v0.0.0-20160126235308-23def4e6c14b
 	github.com/golang/mock v1.1.1
-	github.com/klauspost/cpuid v0.0.0-20180405133222-e7e905edc00e // indirect
-	github.com/klauspost/reedsolomon v0.0.0-20180704173009-925cb01d6510 // indirect
 	github.com/minio/blake2b-simd v0.0.0-20160723061019-3f5f724cb5b1
 	github.com/pkg/errors v0.8.0
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/testify v1.2.2
+	github.com/uber-go/atomic v1.3.2
+	github.com/xtaci/kcp-go v0.0.0-20180203133237-42bc1dfefff5
+	golang.org/x/crypto v0.0.0-20180718160520-a2144134853f
+	golang.org/x/net v0.0.0-20180712202826-d0887baf81f4
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/huin/goupnp v0.0.0-20180415215157-1395d1447324 // indirect
+	github.com/jackpal/gateway v1.0.4 // indirect
+	github.com/jackpal/go-nat-pmp v1.0.1 // indirect
+	github.com/klauspost/cpuid v0.0.0-20180405133222-e7e905edc00e // indirect
+	github.com/klauspost/reedsolomon v0.0.0-20180704173009-925cb01d6510 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/templexxx/cpufeat v0.0.0-20180714071118-e85c4911a733 // indirect
 	github.com/templexxx/xor v0.0.0-20170926022130-0af8e873c554 // indirect
 	github.com/tjfoc/gmsm v1.0.1 // indirect
-	github.com/uber-go/atomic v1.3.2
-	github.com/xtaci/kcp-go v0.0.0-20180203133237-42bc1dfefff5
-	github.com/xtaci/smux v1.0.7
-	golang.org/x/crypto v0.0.0-20180718160520-a2144134853f // indirect
-	golang.org/x/net v0.0.0-20180712202826-d0887baf81f4 // indirect
+	golang.org/x/sys v0.7.0 // indirect
+	golang.org/x/text v0.3.0 // indirect
 )
@@ -3,6 +3,7 @@ package network
 import (
 	"net"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -12,17 +13,25 @@ import (
 
 var domainLookupCache = lru.NewCache(1000)
 
-// AddressInfo represents a network URL.
+// AddressInfo represents a network URL. Addresses of path-based protocols such as
+// unix://node.sock comprise solely a path, and have no host nor port.
 type AddressInfo struct {
 	Protocol string
 	Host     string
 	Port     uint16
+	Path     string
 }
 
 const (
 	networkClientName = "noise"
 )
 
+// pathProtocols are the protocols whose addresses are filesystem paths rather than hosts
+// and ports.
+var pathProtocols = map[string]struct{}{
+	"unix": {},
+}
+
 // Errors
 var (
 	// ErrStrInvalidAddress returns if an invalid address was given
@@ -48,7 +57,7 @@ func (info *AddressInfo) Network() string {
 // String prints out either the URL representation of the address info, or
 // solely just a joined host and port should a network scheme not be defined.
 func (info *AddressInfo) String() string {
-	address := net.JoinHostPort(info.Host, strconv.Itoa(int(info.Port)))
+	address := info.TransportAddress()
 	if len(info.Protocol) > 0 {
 		address = info.Protocol + "://" + address
 	}
@@ -60,12 +69,22 @@ func (info *AddressInfo) HostPort() string {
 	return net.JoinHostPort(info.Host, strconv.Itoa(int(info.Port)))
 }
 
+// TransportAddress returns the address transport layers dial and listen on, being the
+// address without protocol. Addresses of path-based protocols are solely their path, and
+// others are of the format `host:port` followed by their path, if any.
+func (info *AddressInfo) TransportAddress() string {
+	if _, pathBased := pathProtocols[info.Protocol]; pathBased {
+		return info.Path
+	}
+	return info.HostPort() + info.Path
+}
+
 // FormatAddress properly marshals a destinations information into a string.
 func FormatAddress(protocol string, host string, port uint16) string {
 	return NewAddressInfo(protocol, host, port).String()
 }
 
-// ParseAddress derives a network scheme, host and port of a destinations
+// ParseAddress derives a network scheme, host, port and path of a destinations
 // information. Errors should the provided destination address be malformed.
 func ParseAddress(address string) (*AddressInfo, error) {
 	urlInfo, err := url.Parse(address)
@@ -73,6 +92,18 @@ func ParseAddress(address string) (*AddressInfo, error) {
 		return nil, err
 	}
 
+	if _, pathBased := pathProtocols[urlInfo.Scheme]; pathBased {
+		path := strings.TrimPrefix(address, urlInfo.Scheme+"://")
+		if len(path) == 0 || path == address {
+			return nil, errors.Errorf("%s: %s address has no path", ErrStrInvalidAddress, urlInfo.Scheme)
+		}
+
+		return &AddressInfo{
+			Protocol: urlInfo.Scheme,
+			Path:     path,
+		}, nil
+	}
+
 	host, rawPort, err := net.SplitHostPort(urlInfo.Host)
 	if err != nil {
 		return nil, err
@@ -87,31 +118,35 @@ func ParseAddress(address string) (*AddressInfo, error) {
 		Protocol: urlInfo.Scheme,
 		Host:     host,
 		Port:     uint16(port),
+		Path:     urlInfo.Path,
 	}, nil
 }
 
-// ToUnifiedHost resolves a domain host.
+// ToUnifiedHost resolves a domain host to one of its IP addresses, preferring IPv4 addresses
+// such that hosts reachable over both IPv4 and IPv6 are given the same address everywhere.
+// IP literals are returned in their canonical form.
 func ToUnifiedHost(host string) (string, error) {
 	unifiedHost, err := domainLookupCache.Get(host, func() (interface{}, error) {
-		if net.ParseIP(host) == nil {
-			// Probably a domain name is provided.
-			addresses, err := net.LookupHost(host)
-			if err != nil {
-				return "", errors.New(ErrStrNoAvailableAddresses)
-			}
-			if len(addresses) == 0 {
-				return "", errors.New(ErrStrNoAvailableAddresses)
-			}
+		if ip := net.ParseIP(host); ip != nil {
+			return ip.String(), nil
+		}
 
-			host = addresses[0]
+		// Probably a domain name is provided.
+		addresses, err := net.LookupHost(host)
+		if err != nil {
+			return "", errors.New(ErrStrNoAvailableAddresses)
+		}
+		if len(addresses) == 0 {
+			return "", errors.New(ErrStrNoAvailableAddresses)
+		}
 
-			// Hacky localhost fix.
-			if host == "::1" {
-				host = "127.0.0.1"
+		for _, address := range addresses {
+			if ip := net.ParseIP(address); ip != nil && ip.To4() != nil {
+				return ip.String(), nil
 			}
 		}
 
-		return host, nil
+		return addresses[0], nil
 	})
 
 	if unifiedHost == nil {
@@ -121,6 +156,14 @@ func ToUnifiedHost(host string) (string, error) {
 	return unifiedHost.(string), err
 }
 
+// loopbackHost returns the loopback address of the same IP family as host.
+func loopbackHost(host string) string {
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		return net.IPv6loopback.String()
+	}
+	return "127.0.0.1"
+}
+
 // ToUnifiedAddress resolves and normalizes a network address.
 func ToUnifiedAddress(address string) (string, error) {
 	address = strings.TrimSpace(address)
@@ -133,6 +176,11 @@ func ToUnifiedAddress(address string) (string, error) {
 		return "", err
 	}
 
+	// Paths of path-based addresses are taken as is.
+	if _, pathBased := pathProtocols[info.Protocol]; pathBased {
+		return info.String(), nil
+	}
+
 	info.Host, err = ToUnifiedHost(info.Host)
 	if err != nil {
 		return "", err
@@ -140,3 +188,22 @@ func ToUnifiedAddress(address string) (string, error) {
 
 	return info.String(), nil
 }
+
+// sortByTransportPreference stably sorts addresses by the position of their protocols in
+// preference. Addresses whose protocols are not listed are moved last.
+func sortByTransportPreference(addresses []string, preference []string) {
+	rank := func(address string) int {
+		if info, err := ParseAddress(address); err == nil {
+			for i, protocol := range preference {
+				if info.Protocol == protocol {
+					return i
+				}
+			}
+		}
+		return len(preference)
+	}
+
+	sort.SliceStable(addresses, func(i, j int) bool {
+		return rank(addresses[i]) < rank(addresses[j])
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"reflect"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -93,6 +94,8 @@ func TestToUnifiedHost(t *testing.T) {
 	}{
 		{"tcp://asdf:1000", errors.New(ErrStrNoAvailableAddresses), "", "LookupHost fails to resolve"},
 		{"localhost", nil, "127.0.0.1", "should resolve localhost to 127.0.0.1"},
+		{"0:0:0:0:0:0:0:1", nil, "::1", "should canonicalize IPv6 literals"},
+		{"::ffff:10.0.0.1", nil, "10.0.0.1", "should canonicalize IPv4-mapped IPv6 literals"},
 	}
 	for _, tt := range testCases {
 		address, err := ToUnifiedHost(tt.address)
@@ -118,6 +121,7 @@ func TestParseAddress(t *testing.T) {
 		{"https://[2b01:e34:ef40:7730:8e70:5aff:fefe:edac]:foo/foo", "url.Parse fails"},
 		{"tcp://", "empty url error not triggered"},
 		{"tcp://host:k", "port url error not triggered"},
+		{"unix://", "empty path error not triggered"},
 	}
 	for _, tt := range testCases {
 		_, err := ParseAddress(tt.address)
@@ -135,3 +139,98 @@ func BenchmarkParseAddress(b *testing.B) {
 		}
 	}
 }
+
+func TestSortByTransportPreference(t *testing.T) {
+	t.Parallel()
+
+	addresses := []string{
+		"tcp://127.0.0.1:10000",
+		"quic://127.0.0.1:10001",
+		"kcp://127.0.0.1:10002",
+		"tcp://127.0.0.1:10003",
+	}
+	sortByTransportPreference(addresses, []string{"kcp", "tcp"})
+
+	expected := []string{
+		"kcp://127.0.0.1:10002",
+		"tcp://127.0.0.1:10000",
+		"tcp://127.0.0.1:10003",
+		"quic://127.0.0.1:10001",
+	}
+	if !reflect.DeepEqual(addresses, expected) {
+		t.Errorf("sortByTransportPreference() = %v, expected %v", addresses, expected)
+	}
+}
+
+func TestParseAddressIPv6(t *testing.T) {
+	t.Parallel()
+
+	info, err := ParseAddress("tcp://[2001:db8::1]:3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Host != "2001:db8::1" || info.Port != 3000 {
+		t.Errorf("ParseAddress() = %+v, expected host 2001:db8::1 and port 3000", info)
+	}
+	if info.String() != "tcp://[2001:db8::1]:3000" {
+		t.Errorf("String() = %s, expected tcp://[2001:db8::1]:3000", info.String())
+	}
+	if info.HostPort() != "[2001:db8::1]:3000" {
+		t.Errorf("HostPort() = %s, expected [2001:db8::1]:3000", info.HostPort())
+	}
+}
+
+func TestParseAddressPath(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		address   string
+		expected  AddressInfo
+		transport string
+	}{
+		{"unix:///var/run/noise.sock", AddressInfo{Protocol: "unix", Path: "/var/run/noise.sock"}, "/var/run/noise.sock"},
+		{"unix://noise.sock", AddressInfo{Protocol: "unix", Path: "noise.sock"}, "noise.sock"},
+		{"ws://127.0.0.1:3000/noise", AddressInfo{Protocol: "ws", Host: "127.0.0.1", Port: 3000, Path: "/noise"}, "127.0.0.1:3000/noise"},
+		{"tcp://127.0.0.1:3000", AddressInfo{Protocol: "tcp", Host: "127.0.0.1", Port: 3000}, "127.0.0.1:3000"},
+	}
+	for _, tt := range testCases {
+		info, err := ParseAddress(tt.address)
+		if err != nil {
+			t.Errorf("ParseAddress(%s) = %+v, expected <nil>", tt.address, err)
+			continue
+		}
+
+		if *info != tt.expected {
+			t.Errorf("ParseAddress(%s) = %+v, expected %+v", tt.address, *info, tt.expected)
+		}
+		if info.String() != tt.address {
+			t.Errorf("String() = %s, expected %s", info.String(), tt.address)
+		}
+		if info.TransportAddress() != tt.transport {
+			t.Errorf("TransportAddress() = %s, expected %s", info.TransportAddress(), tt.transport)
+		}
+	}
+
+	if address, err := ToUnifiedAddress("unix://noise.sock"); err != nil || address != "unix://noise.sock" {
+		t.Errorf("ToUnifiedAddress() = %s, %+v, expected unix://noise.sock", address, err)
+	}
+}
+
+func TestLoopbackHost(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		host     string
+		expected string
+	}{
+		{"10.0.0.1", "127.0.0.1"},
+		{"2001:db8::1", "::1"},
+		{"example.com", "127.0.0.1"},
+	}
+	for _, tt := range testCases {
+		if host := loopbackHost(tt.host); host != tt.expected {
+			t.Errorf("loopbackHost(%s) = %s, expected %s", tt.host, host, tt.expected)
+		}
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/crypto/blake2b"
 	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/perlin-network/noise/network/rpc"
 	"github.com/perlin-network/noise/network/transport"
 	"github.com/perlin-network/noise/peer"
 	"github.com/pkg/errors"
@@ -31,8 +33,9 @@ var (
 type Builder struct {
 	opts options
 
-	keys    *crypto.KeyPair
-	address string
+	keys      *crypto.KeyPair
+	address   string
+	addresses []string
 
 	plugins     *PluginList
 	pluginCount int
@@ -49,6 +52,12 @@ var defaultBuilderOptions = options{
 	writeBufferSize:   defaultWriteBufferSize,
 	writeFlushLatency: defaultWriteFlushLatency,
 	writeTimeout:      defaultWriteTimeout,
+	service:           rpc.DefaultService,
+	maxMessageSize:    defaultMaxMessageSize,
+	codec:             AnyCodec{},
+
+	compressionThreshold: defaultCompressionThreshold,
+	loopbackRewrite:      true,
 }
 
 // A BuilderOption sets options such as connection timeout and cryptographic // policies for the network
@@ -79,21 +88,218 @@ func HashPolicy(policy crypto.HashPolicy) BuilderOption {
 }
 
 // RecvWindowSize returns a BuilderOption that sets the receive buffer window
-// size (default: 4096).
+// size, bounding how far ahead of the next expected message a peer's messages
+// may be received. It must be at least 1 (default: 4096).
 func RecvWindowSize(recvWindowSize int) BuilderOption {
 	return func(o *options) {
 		o.recvWindowSize = recvWindowSize
 	}
 }
 
-// SendWindowSize returns a BuilderOption that sets the send buffer window
-// size (default: 4096).
+// SendWindowSize returns a BuilderOption that sets the maximum number of
+// messages that may be sent to a peer without being acknowledged, or disables
+// the limit if zero (default: 4096).
 func SendWindowSize(sendWindowSize int) BuilderOption {
 	return func(o *options) {
 		o.sendWindowSize = sendWindowSize
 	}
 }
 
+// Backpressure returns a BuilderOption that sets what happens to messages
+// written to a peer whose send window is full (default: BackpressureBlock).
+func Backpressure(policy BackpressurePolicy) BuilderOption {
+	return func(o *options) {
+		o.backpressure = policy
+	}
+}
+
+// MaxMessageSize returns a BuilderOption that sets the maximum size of messages
+// too large for a single frame, which are sent in chunks and reassembled by the
+// receiving peer (default: 64MB).
+func MaxMessageSize(byteSize int) BuilderOption {
+	return func(o *options) {
+		o.maxMessageSize = byteSize
+	}
+}
+
+// MessagePriority returns a BuilderOption that assigns a class to all messages
+// of the same type as message, determining how soon they are written to peers
+// relative to other messages (default: PriorityRequest). The class of a single
+// message may be overridden by writing it with a context from WithPriority.
+func MessagePriority(message proto.Message, priority Priority) BuilderOption {
+	return func(o *options) {
+		// Copy the registered classes, as options are copied from defaults.
+		priorities := make(map[reflect.Type]Priority, len(o.priorities)+1)
+		for typ, priority := range o.priorities {
+			priorities[typ] = priority
+		}
+		priorities[reflect.TypeOf(message)] = priority
+
+		o.priorities = priorities
+	}
+}
+
+// WireCodec returns a BuilderOption that sets the codec messages are encoded
+// with when sent to peers (default: AnyCodec). Peers must use the same codec.
+func WireCodec(codec Codec) BuilderOption {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+// Compression returns a BuilderOption that sets the compression algorithms
+// offered to peers, in order of preference (default: none). Connections are
+// compressed with the algorithm most preferred by the dialing peer which both
+// peers support.
+func Compression(compressors ...Compressor) BuilderOption {
+	return func(o *options) {
+		o.compressors = compressors
+	}
+}
+
+// CompressionThreshold returns a BuilderOption that sets the size below which
+// messages are sent uncompressed over compressed connections (default: 1024
+// bytes).
+func CompressionThreshold(byteSize int) BuilderOption {
+	return func(o *options) {
+		o.compressionThreshold = byteSize
+	}
+}
+
+// TransportPreference returns a BuilderOption that sets the order in which the
+// transports of peers listening on several addresses are dialed, by protocol
+// (default: the order advertised by the peer). Protocols not listed are dialed
+// last.
+func TransportPreference(protocols ...string) BuilderOption {
+	return func(o *options) {
+		o.transportPreference = protocols
+	}
+}
+
+// ListenHost returns a BuilderOption that sets the host of the interface the
+// network listens on, such as "127.0.0.1" or "::1" (default: all interfaces over
+// both IPv4 and IPv6). Peers still dial the network at its address.
+func ListenHost(host string) BuilderOption {
+	return func(o *options) {
+		o.listenHost = host
+	}
+}
+
+// LoopbackRewrite returns a BuilderOption that sets whether peers sharing the
+// host of the network's address are dialed over the loopback interface, which
+// requires them to listen on it (default: true).
+func LoopbackRewrite(enabled bool) BuilderOption {
+	return func(o *options) {
+		o.loopbackRewrite = enabled
+	}
+}
+
+// tcpOption returns a BuilderOption applying configure to the TCP transport layers
+// registered with the builder once the network is built.
+func tcpOption(configure func(*transport.TCP)) BuilderOption {
+	return func(o *options) {
+		// Copy the registered options, as options are copied from defaults.
+		tcpOptions := make([]func(*transport.TCP), 0, len(o.tcpOptions)+1)
+		tcpOptions = append(tcpOptions, o.tcpOptions...)
+
+		o.tcpOptions = append(tcpOptions, configure)
+	}
+}
+
+// kcpOption returns a BuilderOption applying configure to the KCP transport layers
+// registered with the builder once the network is built.
+func kcpOption(configure func(*transport.KCP)) BuilderOption {
+	return func(o *options) {
+		// Copy the registered options, as options are copied from defaults.
+		kcpOptions := make([]func(*transport.KCP), 0, len(o.kcpOptions)+1)
+		kcpOptions = append(kcpOptions, o.kcpOptions...)
+
+		o.kcpOptions = append(kcpOptions, configure)
+	}
+}
+
+// TCPNoDelay returns a BuilderOption that sets whether the TCP transport layers
+// registered with the builder send segments as soon as possible rather than
+// coalescing small writes (default: false).
+func TCPNoDelay(enabled bool) BuilderOption {
+	return tcpOption(func(t *transport.TCP) {
+		t.NoDelay = enabled
+	})
+}
+
+// TCPKeepAlive returns a BuilderOption that sets the period between keep-alive
+// probes of idle connections of the TCP transport layers registered with the
+// builder, or disables keep-alives if zero (default: 3 minutes).
+func TCPKeepAlive(period time.Duration) BuilderOption {
+	return tcpOption(func(t *transport.TCP) {
+		t.KeepAlive = period
+	})
+}
+
+// TCPBufferSizes returns a BuilderOption that sets the sizes of the socket
+// buffers of connections of the TCP transport layers registered with the
+// builder, or leaves them up to the operating system if zero (default: 0).
+func TCPBufferSizes(readBufferSize int, writeBufferSize int) BuilderOption {
+	return tcpOption(func(t *transport.TCP) {
+		t.ReadBufferSize = readBufferSize
+		t.WriteBufferSize = writeBufferSize
+	})
+}
+
+// KCPWindowSize returns a BuilderOption that sets the send and receive window
+// sizes of sessions of the KCP transport layers registered with the builder, in
+// segments (default: 10000).
+func KCPWindowSize(sendWindowSize int, recvWindowSize int) BuilderOption {
+	return kcpOption(func(t *transport.KCP) {
+		t.SendWindowSize = sendWindowSize
+		t.RecvWindowSize = recvWindowSize
+	})
+}
+
+// KCPNoDelay returns a BuilderOption that tunes how eagerly sessions of the KCP
+// transport layers registered with the builder send and retransmit segments:
+// whether to send segments without delay, the interval between flushes (10ms to
+// 5s), the number of acknowledgements skipping a segment after which it is resent
+// (0 to disable fast resends), and whether to disable congestion control
+// (default: true, 20ms, 2, true).
+func KCPNoDelay(noDelay bool, interval time.Duration, resend int, noCongestion bool) BuilderOption {
+	return kcpOption(func(t *transport.KCP) {
+		t.NoDelay = noDelay
+		t.Interval = interval
+		t.Resend = resend
+		t.NoCongestion = noCongestion
+	})
+}
+
+// KCPMTU returns a BuilderOption that sets the maximum size of datagrams sent by
+// sessions of the KCP transport layers registered with the builder, between 50
+// and 1500 bytes (default: 1400 bytes).
+func KCPMTU(mtu int) BuilderOption {
+	return kcpOption(func(t *transport.KCP) {
+		t.MTU = mtu
+	})
+}
+
+// KCPStreamMode returns a BuilderOption that sets whether sessions of the KCP
+// transport layers registered with the builder merge messages into segments as
+// a stream (default: false).
+func KCPStreamMode(enabled bool) BuilderOption {
+	return kcpOption(func(t *transport.KCP) {
+		t.StreamMode = enabled
+	})
+}
+
+// KCPShards returns a BuilderOption that sets the numbers of Reed-Solomon data
+// and parity shards messages sent over the KCP transport layers registered with
+// the builder are split into for forward error correction, or disables it if
+// both are zero (default: 0, 0). Peers must use the same numbers.
+func KCPShards(dataShards int, parityShards int) BuilderOption {
+	return kcpOption(func(t *transport.KCP) {
+		t.DataShards = dataShards
+		t.ParityShards = parityShards
+	})
+}
+
 // WriteBufferSize returns a BuilderOption that sets the write buffer size
 // (default: 4096 bytes).
 func WriteBufferSize(byteSize int) BuilderOption {
@@ -118,6 +324,40 @@ func WriteTimeout(d time.Duration) BuilderOption {
 	}
 }
 
+// EncryptSessions returns a BuilderOption that sets whether connections are
+// encrypted with keys derived from an ephemeral key exchange performed during
+// the handshake (default: false). Peers must agree on this setting.
+func EncryptSessions(enabled bool) BuilderOption {
+	return func(o *options) {
+		o.encryptSessions = enabled
+	}
+}
+
+// MessageAuthentication returns a BuilderOption that sets how messages sent to
+// peers are authenticated (default: AuthSignature).
+func MessageAuthentication(mode AuthMode) BuilderOption {
+	return func(o *options) {
+		o.authMode = mode
+	}
+}
+
+// OrderedDelivery returns a BuilderOption that sets whether messages from a peer
+// are handed to plugins one at a time in the order they were sent, rather than
+// concurrently (default: false).
+func OrderedDelivery(enabled bool) BuilderOption {
+	return func(o *options) {
+		o.orderedDelivery = enabled
+	}
+}
+
+// RPCService returns a BuilderOption that sets the service routing incoming
+// requests to typed handlers (default: rpc.DefaultService).
+func RPCService(service *rpc.Service) BuilderOption {
+	return func(o *options) {
+		o.service = service
+	}
+}
+
 // NewBuilder returns a new builder with default options.
 func NewBuilder() *Builder {
 	builder := &Builder{
@@ -130,6 +370,9 @@ func NewBuilder() *Builder {
 	// Register default transport layers.
 	builder.RegisterTransportLayer("tcp", transport.NewTCP())
 	builder.RegisterTransportLayer("kcp", transport.NewKCP())
+	builder.RegisterTransportLayer("memory", transport.NewMemory())
+	builder.RegisterTransportLayer("unix", transport.NewUnix())
+	builder.RegisterTransportLayer("ws", transport.NewWebSocket())
 
 	return builder
 }
@@ -155,6 +398,13 @@ func (builder *Builder) SetAddress(address string) {
 	builder.address = address
 }
 
+// AddAddress adds an address for the network to listen on besides the address set,
+// such as one over another transport. Peers are advertised all addresses, and may
+// dial the network over any of them.
+func (builder *Builder) AddAddress(address string) {
+	builder.addresses = append(builder.addresses, address)
+}
+
 // AddPluginWithPriority registers a new plugin onto the network with a set priority.
 func (builder *Builder) AddPluginWithPriority(priority int, plugin PluginInterface) error {
 	// Initialize plugin list if not exist.
@@ -190,6 +440,44 @@ func (builder *Builder) ClearTransportLayers() {
 	builder.transports = new(sync.Map)
 }
 
+// configureTransport returns a copy of a transport layer with the TCP and KCP options of the
+// builder applied, including to the TCP layers TLS and WebSockets run over and to layers
+// wrapped by a fault injector. The layer registered with the builder is left as is, such
+// that a layer registered with several builders is configured by each of them separately.
+func (o *options) configureTransport(layer transport.Layer) transport.Layer {
+	switch layer := layer.(type) {
+	case *transport.TCP:
+		configured := *layer
+		for _, configure := range o.tcpOptions {
+			configure(&configured)
+		}
+		return &configured
+	case *transport.KCP:
+		configured := *layer
+		for _, configure := range o.kcpOptions {
+			configure(&configured)
+		}
+		return &configured
+	case *transport.TLS:
+		configured := *layer
+		if layer.TCP != nil {
+			configured.TCP = o.configureTransport(layer.TCP).(*transport.TCP)
+		}
+		return &configured
+	case *transport.WebSocket:
+		configured := *layer
+		if layer.TCP != nil {
+			configured.TCP = o.configureTransport(layer.TCP).(*transport.TCP)
+		}
+		return &configured
+	case *transport.Faulty:
+		configured := *layer
+		configured.Layer = o.configureTransport(layer.Layer)
+		return &configured
+	}
+	return layer
+}
+
 // Build verifies all parameters of the network and returns either an error due to
 // misconfiguration, or a *Network.
 func (builder *Builder) Build() (*Network, error) {
@@ -201,6 +489,33 @@ func (builder *Builder) Build() (*Network, error) {
 		return nil, errors.New(ErrStrNoAddress)
 	}
 
+	// A receive window of no messages would have every message be rejected.
+	if builder.opts.recvWindowSize < 1 {
+		return nil, errors.Errorf("builder: receive window size must be at least 1, got %d", builder.opts.recvWindowSize)
+	}
+
+	// Configure copies of the transport layers with the builder's options, and verify the
+	// options of those which may be misconfigured.
+	var err error
+	transports := new(sync.Map)
+	builder.transports.Range(func(name, layer interface{}) bool {
+		layer = builder.opts.configureTransport(layer.(transport.Layer))
+		transports.Store(name, layer)
+
+		if validator, ok := layer.(interface {
+			Validate() error
+		}); ok {
+			if err = validator.Validate(); err != nil {
+				err = errors.Wrapf(err, "builder: transport layer %q is misconfigured", name)
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize plugin list if not exist.
 	if builder.plugins == nil {
 		builder.plugins = NewPluginList()
@@ -215,6 +530,17 @@ func (builder *Builder) Build() (*Network, error) {
 
 	id := peer.CreateID(unifiedAddress, builder.keys.PublicKey)
 
+	for _, address := range builder.addresses {
+		unified, err := ToUnifiedAddress(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if unified != unifiedAddress && !containsString(id.Addresses, unified) {
+			id.Addresses = append(id.Addresses, unified)
+		}
+	}
+
 	net := &Network{
 		opts:    builder.opts,
 		ID:      id,
@@ -223,7 +549,7 @@ func (builder *Builder) Build() (*Network, error) {
 
 		Plugins: builder.plugins,
 
-		Transports: builder.transports,
+		Transports: transports,
 
 		Peers: new(sync.Map),
 
@@ -231,7 +557,11 @@ func (builder *Builder) Build() (*Network, error) {
 
 		Listening: make(chan struct{}),
 
+		acceptedStreams: make(chan *Stream, acceptBacklog),
+
 		kill: make(chan struct{}),
+
+		closing: make(chan struct{}),
 	}
 
 	net.Init()
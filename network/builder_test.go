@@ -8,6 +8,7 @@ import (
 
 	"github.com/perlin-network/noise/crypto/blake2b"
 	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/perlin-network/noise/network/transport"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -146,6 +147,12 @@ func TestWindowSize(t *testing.T) {
 	}
 	assert.Equal(t, net.opts.recvWindowSize, recvWindowSize, "recv window size given should match found")
 	assert.Equal(t, net.opts.sendWindowSize, sendWindowSize, "send window size given should match found")
+
+	for _, size := range []int{0, -1} {
+		if _, err := NewBuilderWithOptions(RecvWindowSize(size)).Build(); err == nil {
+			t.Errorf("Build() = <nil>, expected an error for a receive window size of %d", size)
+		}
+	}
 }
 
 func TestWriteBufferSize(t *testing.T) {
@@ -214,3 +221,88 @@ func TestPeers(t *testing.T) {
 }
 
 // Broadcast functions are tested through examples.
+
+func TestTransportOptions(t *testing.T) {
+	t.Parallel()
+
+	builder := NewBuilderWithOptions(
+		TCPNoDelay(true),
+		TCPKeepAlive(time.Minute),
+		KCPNoDelay(false, 50*time.Millisecond, 0, false),
+		KCPMTU(1200),
+	)
+
+	net, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	layer, _ := net.Transports.Load("tcp")
+	if tcp := layer.(*transport.TCP); !tcp.NoDelay || tcp.KeepAlive != time.Minute {
+		t.Errorf("expected options to configure the tcp transport layer, got %+v", tcp)
+	}
+
+	layer, _ = net.Transports.Load("kcp")
+	if kcp := layer.(*transport.KCP); kcp.NoDelay || kcp.Interval != 50*time.Millisecond || kcp.MTU != 1200 {
+		t.Errorf("expected options to configure the kcp transport layer, got %+v", kcp)
+	}
+
+	layer, _ = net.Transports.Load("ws")
+	if tcp := layer.(*transport.WebSocket).TCP; !tcp.NoDelay {
+		t.Errorf("expected options to configure the tcp transport layer websockets run over, got %+v", tcp)
+	}
+
+	// Defaults are left untouched.
+	if len(defaultBuilderOptions.tcpOptions) != 0 || len(defaultBuilderOptions.kcpOptions) != 0 {
+		t.Error("expected options not to modify the default options")
+	}
+
+	// Layers registered in place of the defaults are configured too.
+	builder = NewBuilderWithOptions(TCPNoDelay(true))
+	builder.ClearTransportLayers()
+
+	builder.RegisterTransportLayer("tcp", transport.NewTCP())
+
+	if net, err = builder.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	if layer, _ := net.Transports.Load("tcp"); !layer.(*transport.TCP).NoDelay {
+		t.Errorf("expected options to configure a tcp transport layer registered by the caller, got %+v", layer)
+	}
+
+	if _, err := NewBuilderWithOptions(KCPShards(10, 0)).Build(); err == nil {
+		t.Error("expected building with misconfigured transport layers to fail")
+	}
+}
+
+func TestSharedTransportLayer(t *testing.T) {
+	t.Parallel()
+
+	shared := transport.NewTCP()
+
+	var nets []*Network
+	for _, noDelay := range []bool{true, false} {
+		builder := NewBuilderWithOptions(TCPNoDelay(noDelay))
+		builder.SetKeys(ed25519.RandomKeyPair())
+		builder.ClearTransportLayers()
+		builder.RegisterTransportLayer("tcp", shared)
+
+		net, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		nets = append(nets, net)
+	}
+
+	// Each network is configured with the options of its own builder.
+	for i, noDelay := range []bool{true, false} {
+		if layer, _ := nets[i].Transports.Load("tcp"); layer.(*transport.TCP).NoDelay != noDelay {
+			t.Errorf("expected network %d to have no delay set to %t, got %+v", i, noDelay, layer)
+		}
+	}
+
+	if shared.NoDelay {
+		t.Errorf("expected the layer registered to be left as is, got %+v", shared)
+	}
+}
@@ -0,0 +1,184 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxFrameSize is the maximum size of a single message frame.
+	maxFrameSize = 4e6
+
+	// maxUnchunkedSize is the maximum size of a message sent in a single frame, leaving
+	// room for the nonce and MAC assigned to it once written to a connection.
+	maxUnchunkedSize = maxFrameSize - 1024
+
+	// chunkSize is the size of the chunks messages too large to be sent in a single frame
+	// are split into.
+	chunkSize = 1 << 20
+)
+
+// writeChunked splits a message too large to be sent in a single frame into chunks, which
+// are written in order over a connection.
+func (n *Network) writeChunked(ctx context.Context, state *ConnState, address string, message *protobuf.Message, policy BackpressurePolicy, priority Priority) error {
+	payload, err := proto.Marshal(message.Message)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+
+	if len(payload) > n.opts.maxMessageSize {
+		return errors.Errorf("network: message of %d bytes exceeds the maximum message size of %d bytes", len(payload), n.opts.maxMessageSize)
+	}
+
+	chunks := splitChunks(payload, atomic.AddUint64(&state.transfers, 1), n.opts.hashPolicy)
+
+	for _, chunk := range chunks {
+		msg, err := n.PrepareMessage(chunk)
+		if err != nil {
+			return err
+		}
+
+		msg.RequestNonce = message.RequestNonce
+		msg.ReplyFlag = message.ReplyFlag
+
+		err = state.window.Reserve(ctx, policy)
+		if err == errMessageDropped {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to write to %s", address)
+		}
+
+		// Only whole messages may be dropped or failed: once its first chunk is sent,
+		// the rest of a message waits for room in the send window.
+		policy = BackpressureBlock
+
+		if err := n.write(state, msg, priority, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitChunks splits a marshalled message into chunks of a transfer.
+func splitChunks(payload []byte, transfer uint64, hashPolicy crypto.HashPolicy) []*protobuf.Chunk {
+	hash := hashPolicy.HashBytes(payload)
+	total := (len(payload) + chunkSize - 1) / chunkSize
+
+	chunks := make([]*protobuf.Chunk, 0, total)
+
+	for i := 0; i < total; i++ {
+		end := (i + 1) * chunkSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+
+		chunks = append(chunks, &protobuf.Chunk{
+			Transfer: transfer,
+			Index:    uint32(i),
+			Total:    uint32(total),
+			Data:     payload[i*chunkSize : end],
+			Length:   uint64(len(payload)),
+			Hash:     hash,
+		})
+	}
+
+	return chunks
+}
+
+// chunkReassembler reassembles messages out of the chunks received over a connection.
+type chunkReassembler struct {
+	hashPolicy crypto.HashPolicy
+
+	// maxSize bounds the size of all messages being reassembled at once.
+	maxSize  uint64
+	buffered uint64
+
+	transfers map[uint64]*chunkTransfer
+}
+
+// chunkTransfer is a message partially reassembled out of its chunks.
+type chunkTransfer struct {
+	total uint32
+	size  uint64
+	hash  []byte
+
+	next uint32
+	data []byte
+}
+
+func newChunkReassembler(hashPolicy crypto.HashPolicy, maxSize int) *chunkReassembler {
+	return &chunkReassembler{
+		hashPolicy: hashPolicy,
+		maxSize:    uint64(maxSize),
+		transfers:  make(map[uint64]*chunkTransfer),
+	}
+}
+
+// Input buffers a message carrying a decoded chunk, returning the reassembled message once all of
+// its chunks were received, or nil otherwise. Chunks must be input in the order they were
+// sent in.
+func (r *chunkReassembler) Input(msg *protobuf.Message, chunk *protobuf.Chunk) (*protobuf.Message, error) {
+	t, exists := r.transfers[chunk.Transfer]
+	if !exists {
+		if chunk.Index != 0 || chunk.Total == 0 {
+			return nil, errors.Errorf("received chunk %d of unknown transfer %d", chunk.Index, chunk.Transfer)
+		}
+
+		if chunk.Length > r.maxSize-r.buffered {
+			return nil, errors.Errorf("message of %d bytes exceeds the maximum message size of %d bytes", chunk.Length, r.maxSize)
+		}
+
+		t = &chunkTransfer{total: chunk.Total, size: chunk.Length, hash: chunk.Hash}
+
+		r.transfers[chunk.Transfer] = t
+		r.buffered += t.size
+	}
+
+	if chunk.Index != t.next || chunk.Total != t.total || uint64(len(t.data)+len(chunk.Data)) > t.size {
+		r.drop(chunk.Transfer)
+		return nil, errors.Errorf("received malformed chunk %d of transfer %d", chunk.Index, chunk.Transfer)
+	}
+
+	t.data = append(t.data, chunk.Data...)
+	t.next++
+
+	if t.next < t.total {
+		return nil, nil
+	}
+
+	r.drop(chunk.Transfer)
+
+	if uint64(len(t.data)) != t.size || !bytes.Equal(r.hashPolicy.HashBytes(t.data), t.hash) {
+		return nil, errors.Errorf("message reassembled from transfer %d failed its integrity check", chunk.Transfer)
+	}
+
+	payload := new(types.Any)
+	if err := proto.Unmarshal(t.data, payload); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal reassembled message")
+	}
+
+	return &protobuf.Message{
+		Message:      payload,
+		Sender:       msg.Sender,
+		RequestNonce: msg.RequestNonce,
+		ReplyFlag:    msg.ReplyFlag,
+		MessageNonce: msg.MessageNonce,
+	}, nil
+}
+
+// drop stops reassembling the message of a transfer.
+func (r *chunkReassembler) drop(transfer uint64) {
+	if t, exists := r.transfers[transfer]; exists {
+		r.buffered -= t.size
+		delete(r.transfers, transfer)
+	}
+}
@@ -0,0 +1,156 @@
+package network
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/perlin-network/noise/crypto/blake2b"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func chunkMessages(t *testing.T, payload []byte, transfer uint64) []*protobuf.Message {
+	sender := &protobuf.ID{Address: "tcp://localhost:3000", PublicKey: []byte("public key")}
+
+	var msgs []*protobuf.Message
+	for _, chunk := range splitChunks(payload, transfer, blake2b.New()) {
+		raw, err := types.MarshalAny(chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, &protobuf.Message{Message: raw, Sender: sender, RequestNonce: 7})
+	}
+	return msgs
+}
+
+func inputChunk(t *testing.T, r *chunkReassembler, msg *protobuf.Message) (*protobuf.Message, error) {
+	chunk := new(protobuf.Chunk)
+	if err := types.UnmarshalAny(msg.Message, chunk); err != nil {
+		t.Fatal(err)
+	}
+	return r.Input(msg, chunk)
+}
+
+func testPayload(t *testing.T, size int) []byte {
+	raw, err := types.MarshalAny(&protobuf.Bytes{Data: bytes.Repeat([]byte{0xAB}, size)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := proto.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return payload
+}
+
+func TestChunkReassembly(t *testing.T) {
+	t.Parallel()
+
+	r := newChunkReassembler(blake2b.New(), defaultMaxMessageSize)
+
+	a := chunkMessages(t, testPayload(t, 3*chunkSize), 1)
+	b := chunkMessages(t, testPayload(t, 2*chunkSize), 2)
+
+	if len(a) != 4 || len(b) != 3 {
+		t.Fatalf("expected messages to be split into 4 and 3 chunks, got %d and %d", len(a), len(b))
+	}
+
+	// Chunks of different transfers may be interleaved.
+	var reassembled []*protobuf.Message
+	for i := 0; i < len(a); i++ {
+		for _, msgs := range [][]*protobuf.Message{a, b} {
+			if i >= len(msgs) {
+				continue
+			}
+
+			msg, err := inputChunk(t, r, msgs[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg != nil {
+				reassembled = append(reassembled, msg)
+			}
+		}
+	}
+
+	if len(reassembled) != 2 {
+		t.Fatalf("expected 2 reassembled messages, got %d", len(reassembled))
+	}
+
+	for i, size := range []int{2 * chunkSize, 3 * chunkSize} {
+		msg := reassembled[i]
+		if msg.RequestNonce != 7 {
+			t.Errorf("expected request nonce of chunks to be kept, got %d", msg.RequestNonce)
+		}
+
+		data := new(protobuf.Bytes)
+		if err := types.UnmarshalAny(msg.Message, data); err != nil {
+			t.Fatal(err)
+		}
+		if len(data.Data) != size {
+			t.Errorf("expected reassembled message of %d bytes, got %d", size, len(data.Data))
+		}
+	}
+
+	if r.buffered != 0 || len(r.transfers) != 0 {
+		t.Errorf("expected no transfers left buffered, got %d bytes across %d transfers", r.buffered, len(r.transfers))
+	}
+}
+
+func TestChunkReassemblyTampered(t *testing.T) {
+	t.Parallel()
+
+	r := newChunkReassembler(blake2b.New(), defaultMaxMessageSize)
+
+	msgs := chunkMessages(t, testPayload(t, 2*chunkSize), 1)
+
+	// Tamper with the data of the last chunk.
+	chunk := new(protobuf.Chunk)
+	if err := types.UnmarshalAny(msgs[2].Message, chunk); err != nil {
+		t.Fatal(err)
+	}
+	chunk.Data[0] ^= 0xFF
+
+	raw, err := types.MarshalAny(chunk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs[2].Message = raw
+
+	for i, msg := range msgs {
+		_, err := inputChunk(t, r, msg)
+		if i < len(msgs)-1 && err != nil {
+			t.Fatal(err)
+		}
+		if i == len(msgs)-1 && err == nil {
+			t.Error("expected tampered message to fail its integrity check")
+		}
+	}
+}
+
+func TestChunkReassemblyLimits(t *testing.T) {
+	t.Parallel()
+
+	r := newChunkReassembler(blake2b.New(), 2*chunkSize)
+
+	if _, err := inputChunk(t, r, chunkMessages(t, testPayload(t, 3*chunkSize), 1)[0]); err == nil {
+		t.Error("expected message exceeding the maximum message size to be rejected")
+	}
+
+	msgs := chunkMessages(t, testPayload(t, chunkSize), 2)
+	if _, err := inputChunk(t, r, msgs[1]); err == nil {
+		t.Error("expected chunk of unknown transfer to be rejected")
+	}
+
+	if _, err := inputChunk(t, r, msgs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := inputChunk(t, r, msgs[0]); err == nil {
+		t.Error("expected chunk received twice to be rejected")
+	}
+	if len(r.transfers) != 0 {
+		t.Error("expected transfer with a malformed chunk to be dropped")
+	}
+}
@@ -1,6 +1,7 @@
 package network
 
 import (
+	"context"
 	"net"
 	"sync"
 	"sync/atomic"
@@ -24,13 +25,50 @@ type PeerClient struct {
 	Requests     sync.Map // uint64 -> *RequestState
 	RequestNonce uint64
 
+	// Request streams opened by the peer.
+	requestStreams sync.Map // uint64 -> *serverStream
+
+	// Streams multiplexed with the peer.
+	streams     sync.Map // streamKey -> *Stream
+	streamNonce uint32   // for atomic ops
+
 	stream StreamState
 
-	outgoingReady chan struct{}
-	incomingReady chan struct{}
+	// <-ready blocks until a connection to the peer is established. The connection is used
+	// to exchange messages both ways, regardless of which end dialed it.
+	ready chan struct{}
+
+	// <-settled blocks until connecting to the peer either succeeded or failed.
+	settled chan struct{}
+
+	// Serializes establishing a connection to the peer, and guards ID and features once
+	// a connection is established.
+	connMutex sync.Mutex
+
+	// Connections dialed by the peer which are being accepted.
+	accepting inflightTracker
+
+	// Aborts dialing the peer, should the peer connect to us first. Guarded by connMutex.
+	cancelDial context.CancelFunc
 
 	jobs chan func()
 
+	// Plugin callbacks delivering messages in order, should ordered delivery be enabled.
+	deliveries chan func()
+
+	// Protocol version and features negotiated with the peer, guarded by connMutex.
+	features *Features
+
+	// Set once the peer is known to reside at its address, as we dialed it there rather
+	// than it merely claiming the address when dialing us. Guarded by connMutex.
+	verified bool
+
+	// Set once the peer announced that it is shutting down.
+	shuttingDown uint32 // for atomic ops
+
+	// Bytes exchanged with the peer over compressed connections.
+	compression compressionCounters
+
 	closed      uint32 // for atomic ops
 	closeSignal chan struct{}
 }
@@ -47,6 +85,9 @@ type StreamState struct {
 type RequestState struct {
 	data        chan proto.Message
 	closeSignal chan struct{}
+
+	// Set should the request have opened a request stream.
+	stream *RequestStream
 }
 
 // createPeerClient creates a stub peer client.
@@ -61,8 +102,8 @@ func createPeerClient(network *Network, address string) (*PeerClient, error) {
 		Address:      address,
 		RequestNonce: 0,
 
-		incomingReady: make(chan struct{}),
-		outgoingReady: make(chan struct{}),
+		ready:   make(chan struct{}),
+		settled: make(chan struct{}),
 
 		stream: StreamState{
 			buffer:   make([]byte, 0),
@@ -70,6 +111,7 @@ func createPeerClient(network *Network, address string) (*PeerClient, error) {
 		},
 
 		jobs:        make(chan func(), 128),
+		deliveries:  make(chan func(), 128),
 		closeSignal: make(chan struct{}),
 	}
 
@@ -82,6 +124,10 @@ func (c *PeerClient) Init() {
 		plugin.PeerConnect(c)
 	})
 	go c.executeJobs()
+
+	if c.Network.opts.orderedDelivery {
+		go c.executeDeliveries()
+	}
 }
 
 // Submit adds a job to the execution queue.
@@ -93,16 +139,49 @@ func (c *PeerClient) Submit(job func()) {
 }
 
 func (c *PeerClient) executeJobs() {
+	// Deliveries are only queued up by jobs, so none are left to be queued up once the
+	// jobs are done with.
+	defer close(c.deliveries)
+
 	for {
 		select {
 		case job := <-c.jobs:
 			job()
 		case <-c.closeSignal:
-			return
+			// Finish jobs submitted before the client was closed, such as dispatching
+			// replies received right before the peer disconnected.
+			for {
+				select {
+				case job := <-c.jobs:
+					job()
+				default:
+					return
+				}
+			}
 		}
 	}
 }
 
+// deliver queues up a plugin callback to be executed after all previously queued callbacks,
+// returning false should the client be closed before the callback could be queued up. It
+// may only be called by jobs, as deliveries are closed once the jobs are done with.
+func (c *PeerClient) deliver(delivery func()) bool {
+	select {
+	case c.deliveries <- delivery:
+		return true
+	case <-c.closeSignal:
+		return false
+	}
+}
+
+// executeDeliveries executes queued up plugin callbacks one at a time, including those
+// queued up right before the client was closed.
+func (c *PeerClient) executeDeliveries() {
+	for delivery := range c.deliveries {
+		delivery()
+	}
+}
+
 // Close stops all sessions/streams and cleans up the nodes
 // routing table. Errors if session fails to close.
 func (c *PeerClient) Close() error {
@@ -112,6 +191,9 @@ func (c *PeerClient) Close() error {
 
 	close(c.closeSignal)
 
+	c.closeRequestStreams()
+	c.closeStreams()
+
 	c.stream.Lock()
 	c.stream.isClosed = true
 	c.stream.Unlock()
@@ -121,17 +203,22 @@ func (c *PeerClient) Close() error {
 		plugin.PeerDisconnect(c)
 	})
 
+	// The ID is set once a connection is established, under the connection mutex.
+	c.connMutex.Lock()
+	id := c.ID
+	c.connMutex.Unlock()
+
 	// Remove entries from node's network.
-	if c.ID != nil {
+	if id != nil {
 		// close out connections
-		if conn, ok := c.Network.Connections.Load(c.ID.Address); ok {
+		if conn, ok := c.Network.Connections.Load(id.Address); ok {
 			if state, ok := conn.(*ConnState); ok && state != nil {
 				state.conn.Close()
 			}
 		}
 
-		c.Network.Peers.Delete(c.ID.Address)
-		c.Network.Connections.Delete(c.ID.Address)
+		c.Network.Peers.Delete(id.Address)
+		c.Network.Connections.Delete(id.Address)
 	}
 
 	return nil
@@ -139,12 +226,22 @@ func (c *PeerClient) Close() error {
 
 // Tell will asynchronously emit a message to a given peer.
 func (c *PeerClient) Tell(message proto.Message) error {
+	return c.TellContext(context.Background(), message)
+}
+
+// TellContext will asynchronously emit a message to a given peer, giving up should ctx
+// be done before the message could be written.
+func (c *PeerClient) TellContext(ctx context.Context, message proto.Message) error {
+	if c.ShuttingDown() {
+		return ErrPeerShuttingDown
+	}
+
 	signed, err := c.Network.PrepareMessage(message)
 	if err != nil {
 		return errors.Wrap(err, "failed to sign message")
 	}
 
-	err = c.Network.Write(c.Address, signed)
+	err = c.Network.WriteContext(ctx, c.Address, signed)
 	if err != nil {
 		return errors.Wrapf(err, "failed to send message to %s", c.Address)
 	}
@@ -152,21 +249,46 @@ func (c *PeerClient) Tell(message proto.Message) error {
 	return nil
 }
 
-// Request requests for a response for a request sent to a given peer.
+// Request requests for a response for a request sent to a given peer. Should the peer
+// fail to handle the request, the returned error is an *rpc.Error. Requests with no
+// timeout time out after 30 seconds.
 func (c *PeerClient) Request(req *rpc.Request) (proto.Message, error) {
-	signed, err := c.Network.PrepareMessage(req.Message)
-	if err != nil {
-		return nil, err
+	ctx := context.Background()
+	if req.Timeout <= 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultRequestTimeout)
+		defer cancel()
 	}
 
-	signed.RequestNonce = atomic.AddUint64(&c.RequestNonce, 1)
+	return c.RequestContext(ctx, req)
+}
 
-	err = c.Network.Write(c.Address, signed)
+// RequestContext requests for a response for a request sent to a given peer, giving up
+// should ctx be done or the request's timeout elapse before a response comes. Requests
+// with no timeout are only given up upon once ctx is done.
+func (c *PeerClient) RequestContext(ctx context.Context, req *rpc.Request) (proto.Message, error) {
+	if c.ShuttingDown() {
+		return nil, ErrPeerShuttingDown
+	}
+
+	// Requests are waited upon when shutting down.
+	c.Network.inflight.Add()
+	defer c.Network.inflight.Done()
+
+	if req.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, req.Timeout)
+		defer cancel()
+	}
+
+	signed, err := c.Network.PrepareMessage(req.Message)
 	if err != nil {
 		return nil, err
 	}
 
-	// Start tracking the request.
+	signed.RequestNonce = atomic.AddUint64(&c.RequestNonce, 1)
+
+	// Start tracking the request before it is sent, so that no response may be missed.
 	channel := make(chan proto.Message, 1)
 	closeSignal := make(chan struct{})
 
@@ -179,14 +301,41 @@ func (c *PeerClient) Request(req *rpc.Request) (proto.Message, error) {
 	defer close(closeSignal)
 	defer c.Requests.Delete(signed.RequestNonce)
 
+	err = c.Network.WriteContext(ctx, c.Address, signed)
+	if err != nil {
+		return nil, err
+	}
+
 	select {
 	case res := <-channel:
+		if remote, ok := res.(*protobuf.Error); ok {
+			return nil, c.Network.remoteError(remote)
+		}
 		return res, nil
-	case <-time.After(req.Timeout):
-		return nil, errors.New("request timed out")
+	case <-c.Network.kill:
+		return nil, ErrNetworkClosed
+	case <-ctx.Done():
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, errors.New("request timed out")
+		}
+		return nil, errors.Wrap(ctx.Err(), "request cancelled")
 	}
 }
 
+// remoteError converts an error replied by a peer into an *rpc.Error. Details of a type
+// unknown to this node are left out.
+func (n *Network) remoteError(remote *protobuf.Error) *rpc.Error {
+	err := &rpc.Error{Code: remote.Code, Message: remote.Message}
+
+	if remote.Details != nil {
+		if details, decodeErr := n.opts.codec.Decode(remote.Details); decodeErr == nil {
+			err.Details = details
+		}
+	}
+
+	return err
+}
+
 // Reply is equivalent to Write() with an appended nonce to signal a reply.
 func (c *PeerClient) Reply(nonce uint64, message proto.Message) error {
 	signed, err := c.Network.PrepareMessage(message)
@@ -206,6 +355,29 @@ func (c *PeerClient) Reply(nonce uint64, message proto.Message) error {
 	return nil
 }
 
+// Features returns the protocol version and features negotiated with the peer, or nil
+// should no connection to the peer have been established yet.
+func (c *PeerClient) Features() *Features {
+	c.connMutex.Lock()
+	defer c.connMutex.Unlock()
+
+	return c.features
+}
+
+// requireFeature errors should the peer not support a feature.
+func (c *PeerClient) requireFeature(feature string) error {
+	if features := c.Features(); features != nil && !features.Has(feature) {
+		return errors.Errorf("network: peer %s does not support %s", c.Address, feature)
+	}
+	return nil
+}
+
+// CompressionStats returns the number of bytes exchanged with the peer over compressed
+// connections, before and after compression.
+func (c *PeerClient) CompressionStats() CompressionStats {
+	return c.compression.stats()
+}
+
 func (c *PeerClient) handleBytes(pkt []byte) {
 	c.stream.Lock()
 	empty := len(c.stream.buffer) == 0
@@ -307,20 +479,21 @@ func (c *PeerClient) SetWriteDeadline(t time.Time) error {
 	return nil
 }
 
-// IncomingReady returns true if the client has both incoming and outgoing sockets established.
+// IncomingReady returns true if the client has a connection established, waiting up to
+// a second for one to be established.
 func (c *PeerClient) IncomingReady() bool {
-	select {
-	case <-c.incomingReady:
-		return true
-	case <-time.After(1 * time.Second):
-		return false
-	}
+	return c.waitReady()
 }
 
-// OutgoingReady returns true if the client has an outgoing socket established.
+// OutgoingReady returns true if the client has a connection established, waiting up to
+// a second for one to be established.
 func (c *PeerClient) OutgoingReady() bool {
+	return c.waitReady()
+}
+
+func (c *PeerClient) waitReady() bool {
 	select {
-	case <-c.outgoingReady:
+	case <-c.ready:
 		return true
 	case <-time.After(1 * time.Second):
 		return false
@@ -0,0 +1,226 @@
+package network
+
+import (
+	"math"
+	"reflect"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// MinCompactTypeID is the smallest ID messages may be registered under with a CompactCodec.
+// Smaller IDs are reserved for the messages of noise itself.
+const MinCompactTypeID = 64
+
+// Codec encodes messages into the payloads of the messages exchanged with peers, and
+// decodes payloads back into messages. Peers must use the same codec.
+//
+// Handshake messages are always encoded with AnyCodec, so that peers using different
+// codecs reject each other with a clear error.
+type Codec interface {
+	// Name identifies the codec to peers during the handshake.
+	Name() string
+
+	// Encode encodes a message into a payload.
+	Encode(message proto.Message) (*types.Any, error)
+
+	// Decode decodes a payload back into a message.
+	Decode(payload *types.Any) (proto.Message, error)
+
+	// Type returns the type of the message held by a payload without decoding it.
+	Type(payload *types.Any) (reflect.Type, error)
+}
+
+var (
+	_ Codec = AnyCodec{}
+	_ Codec = (*CompactCodec)(nil)
+)
+
+// AnyCodec is the default codec, which encodes messages into google.protobuf.Any payloads
+// denoting the full name of their type. All message types must be registered with
+// github.com/gogo/protobuf/proto.
+type AnyCodec struct{}
+
+// Name implements Codec.
+func (AnyCodec) Name() string {
+	return "any"
+}
+
+// Encode implements Codec.
+func (AnyCodec) Encode(message proto.Message) (*types.Any, error) {
+	return types.MarshalAny(message)
+}
+
+// Decode implements Codec.
+func (AnyCodec) Decode(payload *types.Any) (proto.Message, error) {
+	var ptr types.DynamicAny
+	if err := types.UnmarshalAny(payload, &ptr); err != nil {
+		return nil, err
+	}
+	return ptr.Message, nil
+}
+
+// Type implements Codec.
+func (AnyCodec) Type(payload *types.Any) (reflect.Type, error) {
+	name, err := types.AnyMessageName(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := proto.MessageType(name)
+	if typ == nil {
+		return nil, errors.Errorf("codec: message type %q is not registered", name)
+	}
+	return typ, nil
+}
+
+// compactTypeIDs are the IDs reserved for the messages of noise itself. IDs must never be
+// reassigned, lest peers of different versions misinterpret each others messages.
+var compactTypeIDs = map[uint32]proto.Message{
+	1:  new(protobuf.Ping),
+	2:  new(protobuf.Pong),
+	3:  new(protobuf.LookupNodeRequest),
+	4:  new(protobuf.LookupNodeResponse),
+	5:  new(protobuf.Bytes),
+	6:  new(protobuf.HandshakeRequest),
+	7:  new(protobuf.HandshakeResponse),
+	8:  new(protobuf.Ack),
+	9:  new(protobuf.Error),
+	10: new(protobuf.StreamOpen),
+	11: new(protobuf.StreamEnd),
+	12: new(protobuf.StreamCancel),
+	13: new(protobuf.Chunk),
+	14: new(protobuf.StreamFrame),
+	15: new(protobuf.Goodbye),
+	16: new(protobuf.StreamCredit),
+}
+
+// CompactCodec encodes messages prefixed by a small numeric ID registered for their type
+// instead of the full name of their type, cutting down on the size of every message sent.
+//
+// Message types need not be registered with github.com/gogo/protobuf/proto, but must be
+// registered with the codec under the same ID by all peers.
+type CompactCodec struct {
+	sync.RWMutex
+
+	ids   map[reflect.Type]uint32
+	types map[uint32]reflect.Type
+}
+
+// NewCompactCodec creates a new compact codec with the messages of noise registered.
+func NewCompactCodec() *CompactCodec {
+	c := &CompactCodec{
+		ids:   make(map[reflect.Type]uint32),
+		types: make(map[uint32]reflect.Type),
+	}
+
+	for id, message := range compactTypeIDs {
+		c.register(id, message)
+	}
+
+	return c
+}
+
+// Register registers a message type under an ID, which must be at least MinCompactTypeID.
+// Errors if either the ID or the message type is already registered.
+func (c *CompactCodec) Register(id uint32, message proto.Message) error {
+	if id < MinCompactTypeID {
+		return errors.Errorf("codec: ID %d is reserved", id)
+	}
+
+	typ := reflect.TypeOf(message)
+	if typ == nil || typ.Kind() != reflect.Ptr {
+		return errors.Errorf("codec: message type %T must be a pointer", message)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if existing, exists := c.types[id]; exists {
+		return errors.Errorf("codec: ID %d is already registered to %s", id, existing)
+	}
+
+	if existing, exists := c.ids[typ]; exists {
+		return errors.Errorf("codec: message type %s is already registered under ID %d", typ, existing)
+	}
+
+	c.register(id, message)
+	return nil
+}
+
+func (c *CompactCodec) register(id uint32, message proto.Message) {
+	typ := reflect.TypeOf(message)
+
+	c.ids[typ] = id
+	c.types[id] = typ
+}
+
+// Name implements Codec.
+func (c *CompactCodec) Name() string {
+	return "compact"
+}
+
+// Encode implements Codec.
+func (c *CompactCodec) Encode(message proto.Message) (*types.Any, error) {
+	c.RLock()
+	id, exists := c.ids[reflect.TypeOf(message)]
+	c.RUnlock()
+
+	if !exists {
+		return nil, errors.Errorf("codec: message type %T is not registered", message)
+	}
+
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Any{Value: append(proto.EncodeVarint(uint64(id)), data...)}, nil
+}
+
+// Decode implements Codec.
+func (c *CompactCodec) Decode(payload *types.Any) (proto.Message, error) {
+	typ, n, err := c.decodeType(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	message := reflect.New(typ.Elem()).Interface().(proto.Message)
+	if err := proto.Unmarshal(payload.Value[n:], message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// Type implements Codec.
+func (c *CompactCodec) Type(payload *types.Any) (reflect.Type, error) {
+	typ, _, err := c.decodeType(payload)
+	return typ, err
+}
+
+// decodeType decodes the type of the message held by a payload, alongside the length of
+// its ID prefix.
+func (c *CompactCodec) decodeType(payload *types.Any) (reflect.Type, int, error) {
+	if payload == nil {
+		return nil, 0, errors.New("codec: message is empty")
+	}
+
+	id, n := proto.DecodeVarint(payload.Value)
+	if n == 0 || id > math.MaxUint32 {
+		return nil, 0, errors.New("codec: message has no valid type ID")
+	}
+
+	c.RLock()
+	typ, exists := c.types[uint32(id)]
+	c.RUnlock()
+
+	if !exists {
+		return nil, 0, errors.Errorf("codec: type ID %d is not registered", id)
+	}
+
+	return typ, n, nil
+}
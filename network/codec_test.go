@@ -0,0 +1,91 @@
+package network
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestCompactCodec(t *testing.T) {
+	t.Parallel()
+
+	codec := NewCompactCodec()
+
+	for _, message := range []proto.Message{
+		&protobuf.Ping{},
+		&protobuf.Bytes{Data: []byte("hello")},
+		&protobuf.Error{Code: 3, Message: "failed"},
+	} {
+		payload, err := codec.Encode(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		typ, err := codec.Type(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ != reflect.TypeOf(message) {
+			t.Errorf("expected type %T, got %s", message, typ)
+		}
+
+		decoded, err := codec.Decode(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(decoded, message) {
+			t.Errorf("expected %v to be decoded, got %v", message, decoded)
+		}
+	}
+
+	// Compact payloads are smaller than their google.protobuf.Any counterparts.
+	compact, err := codec.Encode(&protobuf.Ping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	any, err := AnyCodec{}.Encode(&protobuf.Ping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compact.Size() >= any.Size() {
+		t.Errorf("expected compact payload of %d bytes to be smaller than %d bytes", compact.Size(), any.Size())
+	}
+}
+
+func TestCompactCodecRegister(t *testing.T) {
+	t.Parallel()
+
+	codec := NewCompactCodec()
+
+	if err := codec.Register(1, new(types.Empty)); err == nil {
+		t.Error("expected reserved ID to be rejected")
+	}
+	if err := codec.Register(MinCompactTypeID, new(types.Empty)); err != nil {
+		t.Fatal(err)
+	}
+	if err := codec.Register(MinCompactTypeID, new(types.Timestamp)); err == nil {
+		t.Error("expected ID registered twice to be rejected")
+	}
+	if err := codec.Register(MinCompactTypeID+1, new(types.Empty)); err == nil {
+		t.Error("expected message type registered twice to be rejected")
+	}
+
+	if _, err := codec.Encode(new(types.Timestamp)); err == nil {
+		t.Error("expected unregistered message type to fail to encode")
+	}
+
+	// Peers without the message type registered fail to decode it.
+	payload, err := codec.Encode(new(types.Empty))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewCompactCodec().Decode(payload); err == nil {
+		t.Error("expected unregistered type ID to fail to decode")
+	}
+	if _, err := codec.Decode(&types.Any{}); err == nil {
+		t.Error("expected payload without a type ID to fail to decode")
+	}
+}
@@ -0,0 +1,209 @@
+package network
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultCompressionThreshold = 1024
+
+	// frameCompressedFlag is set in the length prefix of frames which are compressed.
+	frameCompressedFlag = 1 << 31
+)
+
+// Compressor compresses the frames written to peers over connections for which it was
+// negotiated during the handshake.
+type Compressor interface {
+	// Name identifies the compression algorithm to peers during the handshake.
+	Name() string
+
+	// Compress compresses a frame.
+	Compress(data []byte) ([]byte, error)
+
+	// Decompress decompresses a frame, failing should it decompress to more than
+	// maxSize bytes.
+	Decompress(data []byte, maxSize int) ([]byte, error)
+}
+
+var _ Compressor = (*FlateCompressor)(nil)
+
+// FlateCompressor compresses frames with DEFLATE.
+type FlateCompressor struct {
+	level   int
+	writers sync.Pool
+}
+
+// NewFlateCompressor creates a new DEFLATE compressor compressing frames at a level
+// ranging from flate.HuffmanOnly to flate.BestCompression. Invalid levels fall back
+// to flate.DefaultCompression.
+func NewFlateCompressor(level int) *FlateCompressor {
+	if level < flate.HuffmanOnly || level > flate.BestCompression {
+		level = flate.DefaultCompression
+	}
+	return &FlateCompressor{level: level}
+}
+
+// Name implements Compressor.
+func (c *FlateCompressor) Name() string {
+	return "flate"
+}
+
+// Compress implements Compressor.
+func (c *FlateCompressor) Compress(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	w, ok := c.writers.Get().(*flate.Writer)
+	if ok {
+		w.Reset(&buffer)
+	} else {
+		var err error
+		if w, err = flate.NewWriter(&buffer, c.level); err != nil {
+			return nil, err
+		}
+	}
+	defer c.writers.Put(w)
+
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Decompress implements Compressor.
+func (c *FlateCompressor) Decompress(data []byte, maxSize int) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+
+	decompressed, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decompressed) > maxSize {
+		return nil, errors.Errorf("frame decompresses to more than %d bytes", maxSize)
+	}
+
+	return decompressed, nil
+}
+
+// negotiateCompressor picks the first compression algorithm preferred by the initiator of
+// a connection which is supported by both ends, or nil should there be none.
+func negotiateCompressor(compressors []Compressor, initiator, responder []string) Compressor {
+	for _, name := range initiator {
+		if !containsString(responder, name) {
+			continue
+		}
+
+		for _, compressor := range compressors {
+			if compressor.Name() == name {
+				return compressor
+			}
+		}
+	}
+
+	return nil
+}
+
+// CompressionStats counts the bytes of frames exchanged with a peer over compressed
+// connections, before and after compression.
+type CompressionStats struct {
+	BytesSent           uint64
+	CompressedBytesSent uint64
+
+	BytesReceived           uint64
+	CompressedBytesReceived uint64
+}
+
+// SendRatio returns the size of the frames sent to the peer after compression relative to
+// their size before compression, or 1 should none have been sent.
+func (s CompressionStats) SendRatio() float64 {
+	return compressionRatio(s.CompressedBytesSent, s.BytesSent)
+}
+
+// ReceiveRatio returns the size of the frames received from the peer before decompression
+// relative to their size after decompression, or 1 should none have been received.
+func (s CompressionStats) ReceiveRatio() float64 {
+	return compressionRatio(s.CompressedBytesReceived, s.BytesReceived)
+}
+
+func compressionRatio(compressed, raw uint64) float64 {
+	if raw == 0 {
+		return 1
+	}
+	return float64(compressed) / float64(raw)
+}
+
+// compressionCounters are the counters of CompressionStats, updated atomically.
+type compressionCounters struct {
+	sent, compressedSent         uint64 // for atomic ops
+	received, compressedReceived uint64 // for atomic ops
+}
+
+func (c *compressionCounters) stats() CompressionStats {
+	return CompressionStats{
+		BytesSent:               atomic.LoadUint64(&c.sent),
+		CompressedBytesSent:     atomic.LoadUint64(&c.compressedSent),
+		BytesReceived:           atomic.LoadUint64(&c.received),
+		CompressedBytesReceived: atomic.LoadUint64(&c.compressedReceived),
+	}
+}
+
+// frameCompression compresses the frames exchanged over a single connection.
+type frameCompression struct {
+	compressor Compressor
+
+	// Frames smaller than threshold are sent uncompressed.
+	threshold int
+
+	counters *compressionCounters
+}
+
+// compress compresses a frame should it be at least as large as the threshold and shrink
+// once compressed. The second returning parameter is true should the frame be compressed.
+func (c *frameCompression) compress(frame []byte) ([]byte, bool, error) {
+	compressed, ok := frame, false
+
+	if len(frame) >= c.threshold {
+		data, err := c.compressor.Compress(frame)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "failed to compress frame")
+		}
+
+		if len(data) < len(frame) {
+			compressed, ok = data, true
+		}
+	}
+
+	atomic.AddUint64(&c.counters.sent, uint64(len(frame)))
+	atomic.AddUint64(&c.counters.compressedSent, uint64(len(compressed)))
+
+	return compressed, ok, nil
+}
+
+// decompress decompresses a frame received compressed should compressed be true.
+func (c *frameCompression) decompress(frame []byte, compressed bool) ([]byte, error) {
+	decompressed := frame
+
+	if compressed {
+		var err error
+		if decompressed, err = c.compressor.Decompress(frame, maxFrameSize); err != nil {
+			return nil, errors.Wrap(err, "failed to decompress frame")
+		}
+	}
+
+	atomic.AddUint64(&c.counters.received, uint64(len(decompressed)))
+	atomic.AddUint64(&c.counters.compressedReceived, uint64(len(frame)))
+
+	return decompressed, nil
+}
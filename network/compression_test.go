@@ -0,0 +1,137 @@
+package network
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// namedCompressor is a compressor advertised under a different name.
+type namedCompressor struct {
+	Compressor
+	name string
+}
+
+func (c namedCompressor) Name() string {
+	return c.name
+}
+
+func TestNegotiateCompressor(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12021, Compression(namedCompressor{NewFlateCompressor(1), "fast"}, NewFlateCompressor(9)))
+	bob := buildHandshakeNetwork(t, 12022, Compression(NewFlateCompressor(9), namedCompressor{NewFlateCompressor(1), "fast"}))
+	carol := buildHandshakeNetwork(t, 12023)
+
+	negotiate := func(initiator, responder *Network) (Compressor, Compressor) {
+		a, b := net.Pipe()
+		defer a.Close()
+		defer b.Close()
+
+		done := make(chan *session, 1)
+		go func() {
+			session, _ := responder.handshake(context.Background(), b, false, false, nil)
+			done <- session
+		}()
+
+		initiatorSession, err := initiator.handshake(context.Background(), a, true, false, nil)
+		responderSession := <-done
+
+		if err != nil || responderSession == nil {
+			t.Fatalf("handshake failed: %+v", err)
+		}
+		return initiatorSession.compressor, responderSession.compressor
+	}
+
+	// Both ends settle on the algorithm most preferred by the initiator.
+	for _, pair := range []struct {
+		initiator, responder *Network
+		expected             string
+	}{
+		{alice, bob, "fast"},
+		{bob, alice, "flate"},
+	} {
+		a, b := negotiate(pair.initiator, pair.responder)
+		if a == nil || b == nil || a.Name() != pair.expected || b.Name() != pair.expected {
+			t.Errorf("expected both ends to negotiate %s, got %v and %v", pair.expected, a, b)
+		}
+	}
+
+	if a, b := negotiate(alice, carol); a != nil || b != nil {
+		t.Errorf("expected no compression with a peer not supporting any, got %v and %v", a, b)
+	}
+}
+
+func TestFrameCompression(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12024)
+
+	counters := new(compressionCounters)
+	compression := &frameCompression{
+		compressor: NewFlateCompressor(flate.DefaultCompression),
+		threshold:  defaultCompressionThreshold,
+		counters:   counters,
+	}
+
+	for _, size := range []int{16, 64 * 1024} {
+		msg, err := alice.signMessage(alice.opts.codec, &protobuf.Bytes{Data: bytes.Repeat([]byte("noise"), size)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var wire bytes.Buffer
+		if err := alice.sendMessage(&wire, msg, new(sync.Mutex), compression); err != nil {
+			t.Fatal(err)
+		}
+		frame := wire.Bytes()
+
+		compressed := frame[0]&0x80 != 0
+		if compressed != (size > defaultCompressionThreshold) {
+			t.Errorf("expected message of %d bytes to be compressed only if above the threshold", size)
+		}
+
+		received, err := alice.receiveMessage(&bufferConn{buffer: bytes.NewBuffer(frame)}, nil, compression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !received.Equal(msg) {
+			t.Error("received message does not match the message sent")
+		}
+
+		if _, err := alice.receiveMessage(&bufferConn{buffer: bytes.NewBuffer(frame)}, nil, nil); compressed && err == nil {
+			t.Error("expected compressed message to be rejected over a connection without compression")
+		}
+	}
+
+	stats := counters.stats()
+	if stats.BytesSent != stats.BytesReceived || stats.CompressedBytesSent != stats.CompressedBytesReceived {
+		t.Errorf("expected bytes sent and received to be counted alike, got %+v", stats)
+	}
+	if ratio := stats.SendRatio(); ratio <= 0 || ratio >= 0.1 {
+		t.Errorf("expected repetitive messages to compress well, got a ratio of %f", ratio)
+	}
+}
+
+func TestFlateCompressorLimit(t *testing.T) {
+	t.Parallel()
+
+	c := NewFlateCompressor(flate.BestSpeed)
+
+	data, err := c.Compress(make([]byte, 4096))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Decompress(data, 4096); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Decompress(data, 4095); err == nil {
+		t.Error("expected frame decompressing past the maximum size to be rejected")
+	}
+}
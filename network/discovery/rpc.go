@@ -13,7 +13,7 @@ import (
 )
 
 func queryPeerByID(net *network.Network, peerID peer.ID, targetID peer.ID, responses chan []*protobuf.ID) {
-	client, err := net.Client(peerID.Address)
+	client, err := net.ClientByID(peerID)
 	if err != nil {
 		responses <- []*protobuf.ID{}
 		return
@@ -0,0 +1,95 @@
+package network
+
+import (
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProtocolVersion is the version of the protocol spoken by this node. It is bumped
+	// whenever the wire format changes.
+	ProtocolVersion = 1
+
+	// MinProtocolVersion is the oldest protocol version spoken by peers this node may
+	// connect to.
+	MinProtocolVersion = 1
+)
+
+// Optional features a node may support, advertised to peers during the handshake.
+const (
+	// FeatureEncryption denotes support for encrypted sessions.
+	FeatureEncryption = "encryption"
+
+	// FeatureChunking denotes support for messages sent in chunks.
+	FeatureChunking = "chunking"
+
+	// FeatureStreams denotes support for streams multiplexed over a connection.
+	FeatureStreams = "streams"
+
+	// FeatureRequestStreams denotes support for request streams.
+	FeatureRequestStreams = "request-streams"
+)
+
+// supportedFeatures are the features supported by this node.
+var supportedFeatures = []string{
+	FeatureEncryption,
+	FeatureChunking,
+	FeatureStreams,
+	FeatureRequestStreams,
+}
+
+// Features are the protocol version and features negotiated with a peer.
+type Features struct {
+	// Version is the protocol version spoken with the peer, being the older of the
+	// versions of both ends.
+	Version uint32
+
+	// Codec is the name of the codec messages are encoded with.
+	Codec string
+
+	// Compression is the name of the compression algorithm messages written to the peer
+	// are compressed with, or empty should they be sent uncompressed.
+	Compression string
+
+	// Encrypted is true should the session with the peer be encrypted.
+	Encrypted bool
+
+	// Features supported by both ends.
+	supported []string
+}
+
+// Has returns true should a feature be supported by both ends.
+func (f *Features) Has(feature string) bool {
+	return containsString(f.supported, feature)
+}
+
+// negotiateFeatures checks that a peer is compatible with this node given the hello
+// it sent during the handshake, and returns the features both ends support.
+func (n *Network) negotiateFeatures(id *peer.ID, hello *protobuf.HandshakeRequest) (*Features, error) {
+	if hello.Version < MinProtocolVersion {
+		return nil, errors.Errorf("handshake: peer %s speaks protocol version %d, but at least version %d is required", id.Address, hello.Version, MinProtocolVersion)
+	}
+
+	if hello.Codec != n.opts.codec.Name() {
+		return nil, errors.Errorf("handshake: peer %s encodes messages with the %q codec, but this node uses the %q codec", id.Address, hello.Codec, n.opts.codec.Name())
+	}
+
+	features := &Features{
+		Version:   ProtocolVersion,
+		Codec:     hello.Codec,
+		Encrypted: n.opts.encryptSessions,
+	}
+
+	if hello.Version < features.Version {
+		features.Version = hello.Version
+	}
+
+	for _, feature := range supportedFeatures {
+		if containsString(hello.Features, feature) {
+			features.supported = append(features.supported, feature)
+		}
+	}
+
+	return features, nil
+}
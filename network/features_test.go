@@ -0,0 +1,54 @@
+package network
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestNegotiateFeatures(t *testing.T) {
+	t.Parallel()
+
+	n := buildHandshakeNetwork(t, 12025)
+	id := &peer.ID{Address: "tcp://localhost:12026"}
+
+	features, err := n.negotiateFeatures(id, &protobuf.HandshakeRequest{
+		Version:  ProtocolVersion + 1,
+		Codec:    AnyCodec{}.Name(),
+		Features: []string{FeatureStreams, "teleportation"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if features.Version != ProtocolVersion {
+		t.Errorf("expected the older protocol version %d to be spoken, got %d", ProtocolVersion, features.Version)
+	}
+	if !features.Has(FeatureStreams) {
+		t.Error("expected streams to be supported by both ends")
+	}
+	if features.Has(FeatureRequestStreams) || features.Has("teleportation") {
+		t.Error("expected features not supported by both ends to be left out")
+	}
+
+	if _, err := n.negotiateFeatures(id, &protobuf.HandshakeRequest{Codec: AnyCodec{}.Name()}); err == nil || !strings.Contains(err.Error(), "protocol version") {
+		t.Errorf("expected peer speaking an outdated protocol version to be rejected, got %v", err)
+	}
+}
+
+func TestHandshakeCodecMismatch(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12027)
+	bob := buildHandshakeNetwork(t, 12028, WireCodec(NewCompactCodec()))
+
+	_, _, errA, errB := handshakePair(t, alice, bob)
+
+	for _, err := range []error{errA, errB} {
+		if err == nil || !strings.Contains(err.Error(), "codec") {
+			t.Errorf("expected peers using different codecs to reject each other, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"net"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/network/transport"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// handshakeChallengeSize is the number of random bytes a peer must sign over to
+// prove possession of its private key.
+const handshakeChallengeSize = 32
+
+// handshakeCodec encodes handshake messages regardless of the codec of the network.
+var handshakeCodec = AnyCodec{}
+
+// handshake authenticates a newly established connection by having both ends
+// answer a random challenge with a message signed by their private key. It
+// returns the session of the connection once the remote peer has proven it owns
+// the public key it advertises.
+//
+// Both ends additionally attach an ephemeral X25519 key to their signed responses
+// from which the keys of the session are derived. Should encrypted sessions be
+// enabled, the connection of the returned session is encrypted with them.
+//
+// Both ends advertise their protocol version, codec, features and compression algorithms
+// alongside their challenges. Incompatible peers are rejected, and the session settles on
+// the compression algorithm the initiator prefers most.
+//
+// The responder admits the peer through admit, should it not be nil, before answering
+// the challenge of the peer last. Should admit fail, the handshake fails on both ends.
+//
+// Should probe be set, the initiator only verifies the identity of the peer residing at
+// the address it dialed. The responder then answers without admitting the initiator, and
+// neither end is to establish the session.
+//
+// The handshake is aborted, closing conn, should ctx be done before it completes.
+//
+// Messages are strictly alternated so that the handshake also completes over
+// unbuffered transports. The dialing side (initiator) speaks first:
+//
+//	initiator -> HandshakeRequest{a}
+//	responder -> HandshakeRequest{b}
+//	initiator -> HandshakeResponse{b}
+//	responder -> HandshakeResponse{a}
+func (n *Network) handshake(ctx context.Context, conn net.Conn, initiator, probe bool, admit func(id *peer.ID) error) (result *session, err error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// Interrupt any pending reads and writes should ctx be done halfway through. Not all
+	// transports wake up reads already pending once their deadline is moved, such as KCP,
+	// so the connection is closed.
+	done, interrupted := make(chan struct{}), make(chan bool, 1)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+			interrupted <- true
+		case <-done:
+			interrupted <- false
+		}
+	}()
+
+	defer func() {
+		close(done)
+
+		if <-interrupted && err == nil {
+			result, err = nil, errors.Wrap(ctx.Err(), "handshake: interrupted")
+		}
+
+		conn.SetDeadline(time.Time{})
+	}()
+
+	challenge := make([]byte, handshakeChallengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, errors.Wrap(err, "handshake: failed to generate challenge")
+	}
+
+	ephemeral, err := newEphemeralKeyPair()
+	if err != nil {
+		return nil, err
+	}
+
+	mutex := new(sync.Mutex)
+
+	compressors := make([]string, 0, len(n.opts.compressors))
+	for _, compressor := range n.opts.compressors {
+		compressors = append(compressors, compressor.Name())
+	}
+
+	hello := &protobuf.HandshakeRequest{
+		Challenge:   challenge,
+		Compressors: compressors,
+		Version:     ProtocolVersion,
+		Codec:       n.opts.codec.Name(),
+		Features:    supportedFeatures,
+		Probe:       probe,
+	}
+
+	if initiator {
+		if err := n.sendHandshakeMessage(conn, hello, mutex); err != nil {
+			return nil, err
+		}
+	}
+
+	request := new(protobuf.HandshakeRequest)
+	id, err := n.receiveHandshakeMessage(conn, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(request.Challenge) != handshakeChallengeSize {
+		return nil, errors.Errorf("handshake: peer %s sent a challenge of invalid length %d", id.Address, len(request.Challenge))
+	}
+
+	if !initiator {
+		if err := n.sendHandshakeMessage(conn, hello, mutex); err != nil {
+			return nil, err
+		}
+	}
+
+	// Both hellos are exchanged before either is checked, so that both ends learn of
+	// each others incompatibilities.
+	features, err := n.negotiateFeatures(id, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Both ends settle on the compression algorithm the initiator prefers most.
+	var compressor Compressor
+	if initiator {
+		compressor = negotiateCompressor(n.opts.compressors, compressors, request.Compressors)
+	} else {
+		compressor = negotiateCompressor(n.opts.compressors, request.Compressors, compressors)
+	}
+
+	if compressor != nil {
+		features.Compression = compressor.Name()
+	}
+
+	answer := func() error {
+		return n.sendHandshakeMessage(conn, &protobuf.HandshakeResponse{
+			Challenge:    request.Challenge,
+			EphemeralKey: ephemeral.public[:],
+			Encrypted:    n.opts.encryptSessions,
+		}, mutex)
+	}
+
+	if initiator {
+		if err := answer(); err != nil {
+			return nil, err
+		}
+	}
+
+	response := new(protobuf.HandshakeResponse)
+	responder, err := n.receiveHandshakeMessage(conn, response)
+	if err != nil {
+		return nil, err
+	}
+
+	// The response must be signed by the very same identity that was advertised.
+	if !id.Equals(*responder) || id.Address != responder.Address {
+		return nil, errors.Errorf("handshake: response signed by %s does not match advertised peer %s", responder.Address, id.Address)
+	}
+
+	if !bytes.Equal(response.Challenge, challenge) {
+		return nil, errors.Errorf("handshake: peer %s failed to answer our challenge", id.Address)
+	}
+
+	// Connections secured with a certificate derived from a node's keys must be secured
+	// by the very peer that was authenticated.
+	if publicKey, derived := transport.PeerPublicKey(conn); derived && !bytes.Equal(publicKey, id.PublicKey) {
+		return nil, errors.Errorf("handshake: peer %s secured its connection with the certificate of another node", id.Address)
+	}
+
+	if response.Encrypted && !n.opts.encryptSessions {
+		return nil, errors.Errorf("handshake: peer %s requires an encrypted session", id.Address)
+	}
+
+	if !response.Encrypted && n.opts.encryptSessions {
+		return nil, errors.Errorf("handshake: peer %s does not support encrypted sessions", id.Address)
+	}
+
+	secrets, err := deriveSessionSecrets(ephemeral, response.EphemeralKey, initiator, challenge, request.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	if !initiator {
+		probe = request.Probe
+
+		if admit != nil && !probe {
+			if err := admit(id); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := answer(); err != nil {
+			return nil, err
+		}
+	}
+
+	if n.opts.encryptSessions {
+		if conn, err = newSessionConn(conn, secrets.sendKey, secrets.recvKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return &session{conn: conn, id: id, secrets: secrets, compressor: compressor, features: features, probe: probe}, nil
+}
+
+// sendHandshakeMessage signs and writes a single handshake message directly to a connection.
+// Handshake messages are always signed, regardless of the authentication mode.
+func (n *Network) sendHandshakeMessage(conn net.Conn, message proto.Message, mutex *sync.Mutex) error {
+	msg, err := n.signMessage(handshakeCodec, message)
+	if err != nil {
+		return errors.Wrap(err, "handshake: failed to prepare message")
+	}
+
+	return n.sendMessage(conn, msg, mutex, nil)
+}
+
+// receiveHandshakeMessage reads a single handshake message of the same type as expected into expected, and
+// returns the ID of the peer that signed it.
+func (n *Network) receiveHandshakeMessage(conn net.Conn, expected proto.Message) (*peer.ID, error) {
+	msg, err := n.receiveMessage(conn, nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "handshake: failed to receive message")
+	}
+
+	received, err := handshakeCodec.Decode(msg.Message)
+	if err != nil {
+		return nil, errors.Wrap(err, "handshake: failed to decode message")
+	}
+
+	if reflect.TypeOf(received) != reflect.TypeOf(expected) {
+		return nil, errors.Errorf("handshake: expected %T but received %T", expected, received)
+	}
+
+	proto.Merge(expected, received)
+
+	return (*peer.ID)(msg.Sender), nil
+}
@@ -0,0 +1,134 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func buildHandshakeNetwork(t *testing.T, port uint16, opts ...BuilderOption) *Network {
+	return buildHandshakeNetworkAt(t, fmt.Sprintf("%s://%s:%d", protocol, host, port), opts...)
+}
+
+func buildHandshakeNetworkAt(t *testing.T, address string, opts ...BuilderOption) *Network {
+	builder := NewBuilderWithOptions(opts...)
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(address)
+
+	n, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestHandshake(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12001)
+	bob := buildHandshakeNetwork(t, 12002)
+
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	var bobSession *session
+	var bobErr error
+
+	go func() {
+		defer wg.Done()
+		bobSession, bobErr = bob.handshake(context.Background(), b, false, false, nil)
+	}()
+
+	aliceSession, err := alice.handshake(context.Background(), a, true, false, nil)
+	wg.Wait()
+
+	if err != nil {
+		t.Fatalf("initiator handshake failed: %+v", err)
+	}
+	if bobErr != nil {
+		t.Fatalf("responder handshake failed: %+v", bobErr)
+	}
+
+	aliceSees, bobSees := aliceSession.id, bobSession.id
+
+	if !aliceSees.Equals(bob.ID) || aliceSees.Address != bob.Address {
+		t.Errorf("initiator authenticated %s, expected %s", aliceSees.Address, bob.Address)
+	}
+	if !bobSees.Equals(alice.ID) || bobSees.Address != alice.Address {
+		t.Errorf("responder authenticated %s, expected %s", bobSees.Address, alice.Address)
+	}
+
+	// Both ends must agree on the keys of the session.
+	if !bytes.Equal(aliceSession.secrets.sendMAC, bobSession.secrets.recvMAC) || !bytes.Equal(aliceSession.secrets.recvKey, bobSession.secrets.sendKey) {
+		t.Error("initiator and responder derived different session keys")
+	}
+}
+
+func TestHandshakeWrongChallenge(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12003)
+	mallory := buildHandshakeNetwork(t, 12004)
+
+	a, m := net.Pipe()
+	defer a.Close()
+	defer m.Close()
+
+	// Mallory replies to Alice with a response to a challenge Alice never issued.
+	go func() {
+		if _, err := mallory.receiveMessage(m, nil, nil); err != nil {
+			return
+		}
+		mutex := new(sync.Mutex)
+		challenge := make([]byte, handshakeChallengeSize)
+		if err := mallory.sendHandshakeMessage(m, &protobuf.HandshakeRequest{Challenge: challenge}, mutex); err != nil {
+			return
+		}
+		if _, err := mallory.receiveMessage(m, nil, nil); err != nil {
+			return
+		}
+		mallory.sendHandshakeMessage(m, &protobuf.HandshakeResponse{Challenge: challenge}, mutex)
+	}()
+
+	if _, err := alice.handshake(context.Background(), a, true, false, nil); err == nil {
+		t.Fatal("expected handshake with a peer answering the wrong challenge to fail")
+	}
+}
+
+func TestHandshakeContext(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12005)
+
+	// The other end never answers.
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+
+	go io.Copy(ioutil.Discard, b)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	if _, err := alice.handshake(ctx, a, true, false, nil); err == nil {
+		t.Fatal("expected handshake to fail once its context is cancelled")
+	}
+
+	if elapsed := time.Since(start); elapsed > 1*time.Second {
+		t.Fatalf("handshake took %s to abort after its context was cancelled", elapsed)
+	}
+}
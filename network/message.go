@@ -1,21 +1,34 @@
 package network
 
 import (
+	"context"
+
 	"github.com/gogo/protobuf/proto"
 	"github.com/perlin-network/noise/peer"
 )
 
+type clientContextKey struct{}
+
 // PluginContext provides parameters and helper functions to a Plugin
 // for interacting with/analyzing incoming messages from a select peer.
 type PluginContext struct {
 	client  *PeerClient
 	message proto.Message
 	nonce   uint64
+
+	// Set once a reply was sent, such that no error is replied afterwards.
+	replied bool
 }
 
 // Reply sends back a message to an incoming message's incoming stream.
 func (ctx *PluginContext) Reply(message proto.Message) error {
-	return ctx.client.Reply(ctx.nonce, message)
+	if err := ctx.client.Reply(ctx.nonce, message); err != nil {
+		return err
+	}
+
+	ctx.replied = true
+
+	return nil
 }
 
 // Message returns the decoded protobuf message.
@@ -42,3 +55,15 @@ func (ctx *PluginContext) Self() peer.ID {
 func (ctx *PluginContext) Sender() peer.ID {
 	return *ctx.client.ID
 }
+
+// WithClient returns a copy of ctx carrying a peer client.
+func WithClient(ctx context.Context, client *PeerClient) context.Context {
+	return context.WithValue(ctx, clientContextKey{}, client)
+}
+
+// ClientFromContext returns the peer client carried by ctx, such as the peer which sent the
+// request being handled by an RPC handler. The second returning parameter is false otherwise.
+func ClientFromContext(ctx context.Context) (*PeerClient, bool) {
+	client, ok := ctx.Value(clientContextKey{}).(*PeerClient)
+	return client, ok
+}
@@ -0,0 +1,496 @@
+package network
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+const (
+	// streamWindowSize is the number of bytes which may be sent over a stream before
+	// its reader consumes them.
+	streamWindowSize = 256 * 1024
+
+	// maxStreamFrameSize is the maximum number of bytes sent in a single stream frame.
+	maxStreamFrameSize = 32 * 1024
+
+	// acceptBacklog is the number of streams opened by peers which may wait to be
+	// accepted before further streams are reset.
+	acceptBacklog = 64
+)
+
+var (
+	// ErrStreamReset is returned when using a stream which was reset.
+	ErrStreamReset = errors.New("network: stream was reset")
+
+	// ErrNetworkClosed is returned when accepting streams from a closed network.
+	ErrNetworkClosed = errors.New("network: network is closed")
+
+	errStreamTimeout error = timeoutError{}
+)
+
+// timeoutError is returned by streams once their deadline is exceeded.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "network: stream deadline exceeded" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// streamKey identifies a stream amongst the streams multiplexed with a peer.
+type streamKey struct {
+	id uint32
+
+	// Whether the stream was opened by this node.
+	initiator bool
+}
+
+// Stream is a bidirectional stream of bytes multiplexed with other streams over the
+// messages exchanged with a peer, which implements net.Conn.
+//
+// The bytes in flight over a stream are bounded by a window the reader grants back as
+// bytes are read, such that a slow reader only holds back the writer of its own stream.
+type Stream struct {
+	client *PeerClient
+	key    streamKey
+
+	// Serializes writes such that the bytes of a write are never interleaved with another.
+	writeMutex sync.Mutex
+
+	sync.Mutex
+
+	buffer     []byte
+	consumed   int    // bytes read but not yet granted back to the writer
+	sendWindow uint32 // bytes which may be written before the peer grants more
+
+	readClosed  bool // Close was called
+	writeClosed bool // CloseWrite or Close was called
+	remoteFin   bool // the peer is done writing
+	reset       bool
+
+	readDeadline  time.Time
+	writeDeadline time.Time
+
+	// signal is closed and replaced whenever the state of the stream changes.
+	signal chan struct{}
+}
+
+func newStream(client *PeerClient, key streamKey) *Stream {
+	return &Stream{
+		client:     client,
+		key:        key,
+		sendWindow: streamWindowSize,
+		signal:     make(chan struct{}),
+	}
+}
+
+// OpenStream opens a new stream to the peer.
+func (c *PeerClient) OpenStream() (*Stream, error) {
+	return c.OpenStreamContext(context.Background())
+}
+
+// OpenStreamContext opens a new stream to the peer, giving up should ctx be done
+// before the stream could be opened.
+func (c *PeerClient) OpenStreamContext(ctx context.Context) (*Stream, error) {
+	if c.ShuttingDown() {
+		return nil, ErrPeerShuttingDown
+	}
+	if err := c.requireFeature(FeatureStreams); err != nil {
+		return nil, err
+	}
+
+	s := newStream(c, streamKey{id: atomic.AddUint32(&c.streamNonce, 1), initiator: true})
+	c.streams.Store(s.key, s)
+
+	if err := s.writeFrame(ctx, &protobuf.StreamFrame{Open: true}); err != nil {
+		c.streams.Delete(s.key)
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// AcceptStream waits for and returns the next stream opened by any peer.
+func (n *Network) AcceptStream() (*Stream, error) {
+	return n.AcceptStreamContext(context.Background())
+}
+
+// AcceptStreamContext waits for and returns the next stream opened by any peer, giving
+// up should ctx be done first.
+func (n *Network) AcceptStreamContext(ctx context.Context) (*Stream, error) {
+	select {
+	case s := <-n.acceptedStreams:
+		return s, nil
+	case <-n.kill:
+		return nil, ErrNetworkClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ID returns the ID of the stream, which is unique amongst the streams opened by the
+// same side with a peer.
+func (s *Stream) ID() uint32 {
+	return s.key.id
+}
+
+// Client returns the client of the peer the stream is multiplexed with.
+func (s *Stream) Client() *PeerClient {
+	return s.client
+}
+
+// Read implements net.Conn.
+func (s *Stream) Read(out []byte) (int, error) {
+	for {
+		s.Lock()
+
+		if len(s.buffer) > 0 {
+			n := copy(out, s.buffer)
+			s.buffer = s.buffer[n:]
+			s.consumed += n
+
+			// Grant the window back once half of it was consumed.
+			var grant int
+			if s.consumed >= streamWindowSize/2 && !s.remoteFin {
+				grant, s.consumed = s.consumed, 0
+			}
+			s.Unlock()
+
+			if grant > 0 {
+				if err := s.writeFrame(context.Background(), &protobuf.StreamFrame{Window: uint32(grant)}); err != nil {
+					glog.Warningf("failed to update window of stream with %s [err=%s]", s.client.Address, err)
+				}
+			}
+
+			return n, nil
+		}
+
+		switch {
+		case s.reset:
+			s.Unlock()
+			return 0, ErrStreamReset
+		case s.readClosed:
+			s.Unlock()
+			return 0, ErrStreamClosed
+		case s.remoteFin:
+			s.Unlock()
+			return 0, io.EOF
+		}
+
+		signal, deadline := s.signal, s.readDeadline
+		s.Unlock()
+
+		if err := wait(signal, deadline); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Write implements net.Conn.
+func (s *Stream) Write(data []byte) (int, error) {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	written := 0
+
+	for written < len(data) {
+		s.Lock()
+
+		switch {
+		case s.reset:
+			s.Unlock()
+			return written, ErrStreamReset
+		case s.writeClosed:
+			s.Unlock()
+			return written, ErrStreamClosed
+		}
+
+		deadline := s.writeDeadline
+
+		if s.sendWindow == 0 {
+			signal := s.signal
+			s.Unlock()
+
+			if err := wait(signal, deadline); err != nil {
+				return written, err
+			}
+			continue
+		}
+
+		n := len(data) - written
+		if n > maxStreamFrameSize {
+			n = maxStreamFrameSize
+		}
+		if n > int(s.sendWindow) {
+			n = int(s.sendWindow)
+		}
+		s.sendWindow -= uint32(n)
+		s.Unlock()
+
+		ctx, cancel := deadlineContext(deadline)
+		err := s.writeFrame(ctx, &protobuf.StreamFrame{Data: data[written : written+n]})
+		cancel()
+
+		if err != nil {
+			return written, err
+		}
+
+		written += n
+	}
+
+	return written, nil
+}
+
+// CloseWrite signals to the peer that no more bytes are to be written to the stream,
+// while leaving the stream open for reading.
+func (s *Stream) CloseWrite() error {
+	// Wait for pending writes, lest their bytes be sent after the end of the stream.
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	s.Lock()
+	if s.writeClosed || s.reset {
+		s.Unlock()
+		return nil
+	}
+	s.writeClosed = true
+	done := s.remoteFin
+	s.notify()
+	s.Unlock()
+
+	if done {
+		s.release()
+	}
+
+	return s.writeFrame(context.Background(), &protobuf.StreamFrame{Fin: true})
+}
+
+// Close implements net.Conn by closing the stream both for reading and writing. Bytes
+// the peer keeps on writing to the stream afterwards reset it.
+func (s *Stream) Close() error {
+	s.Lock()
+	s.readClosed = true
+	s.buffer = nil
+	s.notify()
+	s.Unlock()
+
+	return s.CloseWrite()
+}
+
+// Reset aborts the stream on both ends, discarding any bytes in flight.
+func (s *Stream) Reset() error {
+	s.Lock()
+	if s.reset {
+		s.Unlock()
+		return nil
+	}
+	s.reset = true
+	s.buffer = nil
+	s.notify()
+	s.Unlock()
+
+	s.release()
+
+	return s.writeFrame(context.Background(), &protobuf.StreamFrame{Abort: true})
+}
+
+// LocalAddr implements net.Conn.
+func (s *Stream) LocalAddr() net.Addr {
+	return s.client.LocalAddr()
+}
+
+// RemoteAddr implements net.Conn.
+func (s *Stream) RemoteAddr() net.Addr {
+	return s.client.RemoteAddr()
+}
+
+// SetDeadline implements net.Conn.
+func (s *Stream) SetDeadline(t time.Time) error {
+	s.Lock()
+	s.readDeadline = t
+	s.writeDeadline = t
+	s.notify()
+	s.Unlock()
+	return nil
+}
+
+// SetReadDeadline implements net.Conn.
+func (s *Stream) SetReadDeadline(t time.Time) error {
+	s.Lock()
+	s.readDeadline = t
+	s.notify()
+	s.Unlock()
+	return nil
+}
+
+// SetWriteDeadline implements net.Conn.
+func (s *Stream) SetWriteDeadline(t time.Time) error {
+	s.Lock()
+	s.writeDeadline = t
+	s.notify()
+	s.Unlock()
+	return nil
+}
+
+// notify wakes up readers and writers waiting on the stream. The stream must be locked.
+func (s *Stream) notify() {
+	close(s.signal)
+	s.signal = make(chan struct{})
+}
+
+// release stops tracking the stream once it was closed on both ends or reset.
+func (s *Stream) release() {
+	s.client.streams.Delete(s.key)
+}
+
+// writeFrame writes a frame of the stream to the peer. As streams are flow controlled on
+// their own, frames are never dropped nor failed should the send window be full.
+func (s *Stream) writeFrame(ctx context.Context, frame *protobuf.StreamFrame) error {
+	frame.Stream = s.key.id
+	frame.Initiator = s.key.initiator
+
+	msg, err := s.client.Network.PrepareMessage(frame)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign message")
+	}
+
+	if err := s.client.Network.writeContext(ctx, s.client.Address, msg, BackpressureBlock); err != nil {
+		return errors.Wrapf(err, "failed to send message to %s", s.client.Address)
+	}
+
+	return nil
+}
+
+// handleStreamFrame handles a frame of a stream multiplexed with a peer.
+func (n *Network) handleStreamFrame(client *PeerClient, frame *protobuf.StreamFrame) {
+	// Streams opened by the peer are those it is the initiator of.
+	key := streamKey{id: frame.Stream, initiator: !frame.Initiator}
+
+	if frame.Open {
+		if !frame.Initiator {
+			return
+		}
+
+		s := newStream(client, key)
+		if _, loaded := client.streams.LoadOrStore(key, s); loaded {
+			glog.Warningf("peer %s opened stream %d twice", client.Address, frame.Stream)
+			return
+		}
+
+		select {
+		case n.acceptedStreams <- s:
+		default:
+			glog.Warningf("reset stream opened by %s as too many streams are waiting to be accepted", client.Address)
+			s.Reset()
+			return
+		}
+	}
+
+	_s, exists := client.streams.Load(key)
+	if !exists {
+		// Let the peer know the stream it writes to no longer exists.
+		if len(frame.Data) > 0 {
+			resetStream := &Stream{client: client, key: key}
+			if err := resetStream.writeFrame(context.Background(), &protobuf.StreamFrame{Abort: true}); err != nil {
+				glog.Warning(err)
+			}
+		}
+		return
+	}
+	s := _s.(*Stream)
+
+	if frame.Abort {
+		s.Lock()
+		s.reset = true
+		s.buffer = nil
+		s.notify()
+		s.Unlock()
+
+		s.release()
+		return
+	}
+
+	s.Lock()
+
+	if frame.Window > 0 {
+		s.sendWindow += frame.Window
+	}
+
+	if len(frame.Data) > 0 {
+		// Bytes written to a stream closed for reading, or beyond the granted window,
+		// reset the stream.
+		if s.readClosed || s.remoteFin || len(s.buffer)+s.consumed+len(frame.Data) > streamWindowSize {
+			s.Unlock()
+			s.Reset()
+			return
+		}
+
+		s.buffer = append(s.buffer, frame.Data...)
+	}
+
+	done := false
+
+	if frame.Fin {
+		s.remoteFin = true
+		done = s.writeClosed
+	}
+
+	s.notify()
+	s.Unlock()
+
+	if done {
+		s.release()
+	}
+}
+
+// closeStreams resets all streams multiplexed with the peer.
+func (c *PeerClient) closeStreams() {
+	c.streams.Range(func(key, value interface{}) bool {
+		s := value.(*Stream)
+
+		s.Lock()
+		s.reset = true
+		s.notify()
+		s.Unlock()
+
+		c.streams.Delete(key)
+		return true
+	})
+}
+
+// wait waits for a signal, or returns errStreamTimeout once a deadline is exceeded.
+func wait(signal chan struct{}, deadline time.Time) error {
+	if deadline.IsZero() {
+		<-signal
+		return nil
+	}
+
+	timeout := time.Until(deadline)
+	if timeout <= 0 {
+		return errStreamTimeout
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-signal:
+		return nil
+	case <-timer.C:
+		return errStreamTimeout
+	}
+}
+
+// deadlineContext returns a context done once a deadline is exceeded, should it be set.
+func deadlineContext(deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithDeadline(context.Background(), deadline)
+}
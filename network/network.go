@@ -2,30 +2,35 @@ package network
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"math/rand"
 	"net"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/network/rpc"
 	"github.com/perlin-network/noise/network/transport"
 	"github.com/perlin-network/noise/peer"
 	"github.com/perlin-network/noise/protobuf"
 
 	"github.com/gogo/protobuf/proto"
-	"github.com/gogo/protobuf/types"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 )
 
 const (
 	defaultConnectionTimeout = 60 * time.Second
+	defaultRequestTimeout    = 30 * time.Second
 	defaultReceiveWindowSize = 4096
 	defaultSendWindowSize    = 4096
 	defaultWriteBufferSize   = 4096
 	defaultWriteFlushLatency = 50 * time.Millisecond
 	defaultWriteTimeout      = 3 * time.Second
+	defaultMaxMessageSize    = 64 << 20
 )
 
 var contextPool = sync.Pool{
@@ -36,6 +41,13 @@ var contextPool = sync.Pool{
 
 var (
 	_ NetworkInterface = (*Network)(nil)
+
+	// errPeerConnected is returned when connecting to a peer which already has a connection
+	// established, such as when both ends dial each other at once.
+	errPeerConnected = errors.New("network: peer already has a connection established")
+
+	ackType     = reflect.TypeOf(new(protobuf.Ack))
+	goodbyeType = reflect.TypeOf(new(protobuf.Goodbye))
 )
 
 // Network represents the current networking state for this node.
@@ -48,6 +60,10 @@ type Network struct {
 	// Full address to listen on. `protocol://host:port`
 	Address string
 
+	// Addresses advertised by peers listening on several addresses, keyed by their
+	// primary address.
+	addressBook sync.Map
+
 	// Map of plugins registered to the network.
 	// map[string]Plugin
 	Plugins *PluginList
@@ -70,8 +86,23 @@ type Network struct {
 	// <-Listening will block a goroutine until this node is listening for peers.
 	Listening chan struct{}
 
+	// Streams opened by peers waiting to be accepted.
+	acceptedStreams chan *Stream
+
 	// <-kill will begin the server shutdown process
-	kill chan struct{}
+	kill     chan struct{}
+	killOnce sync.Once
+
+	// <-closing stops accepting new peers once the node begins shutting down.
+	closing     chan struct{}
+	closingOnce sync.Once
+
+	// Plugin and RPC handlers and requests in flight, waited upon when shutting down.
+	inflight inflightTracker
+
+	// Set once the network starts serving peers. Plugins are cleaned up once when closing.
+	started     uint32 // for atomic ops
+	cleanupOnce sync.Once
 }
 
 // options for network struct
@@ -84,13 +115,45 @@ type options struct {
 	writeBufferSize   int
 	writeFlushLatency time.Duration
 	writeTimeout      time.Duration
+	encryptSessions   bool
+	authMode          AuthMode
+	orderedDelivery   bool
+	backpressure      BackpressurePolicy
+	service           *rpc.Service
+	maxMessageSize    int
+	priorities        map[reflect.Type]Priority
+	codec             Codec
+
+	compressors          []Compressor
+	compressionThreshold int
+
+	transportPreference []string
+	listenHost          string
+	loopbackRewrite     bool
+
+	// Options applied to the TCP and KCP transport layers registered with the builder.
+	tcpOptions []func(*transport.TCP)
+	kcpOptions []func(*transport.KCP)
 }
 
 type ConnState struct {
-	conn         net.Conn
-	writer       *bufio.Writer
-	messageNonce uint64
-	writerMutex  *sync.Mutex
+	// Number of messages written over the connection in chunks.
+	transfers uint64 // for atomic ops
+
+	conn        net.Conn
+	writer      *bufio.Writer
+	window      *SendWindow
+	scheduler   *writeScheduler
+	writerMutex *sync.Mutex
+
+	// Key authenticating messages written under AuthSessionMAC.
+	macKey []byte
+
+	// Compression of messages written, or nil should none have been negotiated.
+	compression *frameCompression
+
+	// Protocol version and features negotiated with the peer.
+	features *Features
 }
 
 // Init starts all network I/O workers.
@@ -107,133 +170,351 @@ func (n *Network) flushLoop() {
 		case <-n.kill:
 			return
 		case <-t.C:
-			n.Connections.Range(func(key, value interface{}) bool {
-				if state, ok := value.(*ConnState); ok {
-					state.writerMutex.Lock()
-					if err := state.writer.Flush(); err != nil {
-						glog.Warning(err)
-					}
-					state.writerMutex.Unlock()
-				}
-				return true
-			})
+			n.flushConnections()
 		}
 	}
 }
 
+// flushConnections flushes the messages buffered up to be written to all connections.
+func (n *Network) flushConnections() {
+	n.Connections.Range(func(key, value interface{}) bool {
+		if state, ok := value.(*ConnState); ok {
+			state.writerMutex.Lock()
+			if err := state.writer.Flush(); err != nil {
+				glog.Warning(err)
+			}
+			state.writerMutex.Unlock()
+		}
+		return true
+	})
+}
+
 // GetKeys returns the keypair for this network
 func (n *Network) GetKeys() *crypto.KeyPair {
 	return n.keys
 }
 
-func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message) {
+func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message, payload proto.Message) {
 	// Check if the client is ready.
 	if !client.IncomingReady() {
 		return
 	}
-	var ptr types.DynamicAny
-	if err := types.UnmarshalAny(msg.Message, &ptr); err != nil {
-		glog.Error(err)
-		return
+
+	if msg.RequestNonce > 0 && !msg.ReplyFlag {
+		if n.dispatchStreamFrame(client, msg.RequestNonce, payload) {
+			return
+		}
 	}
 
 	if msg.RequestNonce > 0 && msg.ReplyFlag {
 		if _state, exists := client.Requests.Load(msg.RequestNonce); exists {
 			state := _state.(*RequestState)
+			if state.stream != nil {
+				state.stream.dispatch(payload)
+				return
+			}
+
+			// Requests are only replied to once, so any further replies are dropped
+			// rather than holding up the messages of the peer yet to be dispatched.
 			select {
-			case state.data <- ptr.Message:
-			case <-state.closeSignal:
+			case state.data <- payload:
+			default:
 			}
 			return
 		}
+
+		// Credit granted to request streams which already ended is dropped.
+		if _, ok := payload.(*protobuf.StreamCredit); ok {
+			return
+		}
 	}
 
-	switch msgRaw := ptr.Message.(type) {
+	switch msgRaw := payload.(type) {
 	case *protobuf.Bytes:
 		client.handleBytes(msgRaw.Data)
+	case *protobuf.StreamFrame:
+		n.handleStreamFrame(client, msgRaw)
 	default:
+		// Route requests to the handler registered for their type instead of to plugins.
+		if msg.RequestNonce > 0 && !msg.ReplyFlag && n.opts.service != nil {
+			if handler, exists := n.opts.service.Lookup(msgRaw); exists {
+				nonce := msg.RequestNonce
+				n.runHandler(client, func() { n.handleRequest(client, handler, msgRaw, nonce) })
+				return
+			}
+		}
+
 		ctx := contextPool.Get().(*PluginContext)
 		ctx.client = client
 		ctx.message = msgRaw
 		ctx.nonce = msg.RequestNonce
+		ctx.replied = false
+
+		request := msg.RequestNonce > 0 && !msg.ReplyFlag
 
-		go func() {
+		deliver := func() {
 			// Execute 'on receive message' callback for all plugins.
 			n.Plugins.Each(func(plugin PluginInterface) {
-				if err := plugin.Receive(ctx); err != nil {
+				err := rpc.Normalize(plugin.Receive(ctx))
+				if err == nil {
+					return
+				}
+
+				// Forward the first error a plugin fails a request with to the requester,
+				// should no plugin have replied to it yet.
+				if !request || ctx.replied {
 					glog.Errorf("%+v", err)
+					return
 				}
+				ctx.replied = true
+
+				n.replyError(client, ctx.nonce, err)
 			})
 
 			contextPool.Put(ctx)
-		}()
+		}
+
+		n.runHandler(client, deliver)
 	}
 }
 
-// Listen starts listening for peers on a port.
-func (n *Network) Listen() {
+// runHandler runs a plugin or RPC handler of a message received from a peer, one at a time
+// in the order messages were received should ordered delivery be enabled. Running handlers
+// are waited upon when shutting down.
+func (n *Network) runHandler(client *PeerClient, handler func()) {
+	// Handlers are counted as running as soon as they are queued up, such that shutting
+	// down waits for those yet to be delivered.
+	n.inflight.Add()
 
-	// Handle 'network starts listening' callback for plugins.
-	n.Plugins.Each(func(plugin PluginInterface) {
-		plugin.Startup(n)
-	})
+	if n.opts.orderedDelivery {
+		delivered := client.deliver(func() {
+			defer n.inflight.Done()
 
-	// Handle 'network stops listening' callback for plugins.
-	defer func() {
-		n.Plugins.Each(func(plugin PluginInterface) {
-			plugin.Cleanup(n)
+			handler()
 		})
+		if !delivered {
+			n.inflight.Done()
+		}
+		return
+	}
+
+	go func() {
+		defer n.inflight.Done()
+
+		handler()
 	}()
+}
 
-	addrInfo, err := ParseAddress(n.Address)
-	if err != nil {
-		glog.Fatal(err)
+// handleRequest replies to a request with the response of its handler, or with the
+// error the handler failed with.
+func (n *Network) handleRequest(client *PeerClient, handler rpc.Handler, req proto.Message, nonce uint64) {
+	res, err := handler(WithClient(context.Background(), client), req)
+	if err = rpc.Normalize(err); err != nil {
+		n.replyError(client, nonce, err)
+		return
 	}
 
-	var listener net.Listener
+	if err := client.Reply(nonce, res); err != nil {
+		glog.Warningf("failed to reply to request from %s [err=%s]", client.Address, err)
+	}
+}
 
-	if t, exists := n.Transports.Load(addrInfo.Protocol); exists {
-		listener, err = t.(transport.Layer).Listen(int(addrInfo.Port))
+// replyError replies to a request with the error it failed to be handled with.
+func (n *Network) replyError(client *PeerClient, nonce uint64, err error) {
+	rpcErr := rpc.AsError(err)
+
+	reply := &protobuf.Error{Code: rpcErr.Code, Message: rpcErr.Message}
+	if rpcErr.Details != nil {
+		details, err := n.opts.codec.Encode(rpcErr.Details)
 		if err != nil {
-			glog.Fatal(err)
+			glog.Warningf("failed to marshal details of error replied to %s [err=%s]", client.Address, err)
+		} else {
+			reply.Details = details
 		}
-	} else {
-		glog.Fatal("invalid protocol: " + addrInfo.Protocol)
 	}
 
+	if err := client.Reply(nonce, reply); err != nil {
+		glog.Warningf("failed to reply to request from %s [err=%s]", client.Address, err)
+	}
+}
+
+// Listen starts listening for peers on all addresses of the network, and serves them
+// until the network is closed. Errors should the network fail to listen on any of its
+// addresses, or should it already be serving peers.
+func (n *Network) Listen() error {
+	var listeners []net.Listener
+
+	for _, address := range n.Addresses() {
+		listener, err := n.listen(address)
+		if err != nil {
+			for _, listener := range listeners {
+				listener.Close()
+			}
+			return err
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	return n.Serve(listeners...)
+}
+
+// listen listens on the port or path of an address through the transport layer registered
+// for its protocol.
+func (n *Network) listen(address string) (net.Listener, error) {
+	addrInfo, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	t, exists := n.Transports.Load(addrInfo.Protocol)
+	if !exists {
+		return nil, errors.Errorf("network: no transport layer registered for protocol %q", addrInfo.Protocol)
+	}
+
+	// Listen on the host configured rather than the host advertised to peers.
+	if _, pathBased := pathProtocols[addrInfo.Protocol]; !pathBased {
+		addrInfo.Host = n.opts.listenHost
+	}
+
+	listener, err := t.(transport.Layer).Listen(addrInfo.TransportAddress())
+	if err != nil {
+		return nil, errors.Wrapf(err, "network: failed to listen on %s", address)
+	}
+
+	return listener, nil
+}
+
+// Serve serves peers connecting through a number of listeners, such as ones inherited
+// through socket activation, until the network is closed. Peers dial the addresses of the
+// network, which the listeners must therefore be reachable at.
+//
+// The listeners are closed once the network is closed, after which Serve returns nil.
+// Errors should the network already be serving peers, or should any listener fail, in
+// which case all listeners are closed.
+func (n *Network) Serve(listeners ...net.Listener) error {
+	if len(listeners) == 0 {
+		return errors.New("network: no listeners to serve peers through")
+	}
+
+	if !atomic.CompareAndSwapUint32(&n.started, 0, 1) {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+		return errors.New("network: already serving peers")
+	}
+
+	// Handle 'network starts listening' callback for plugins. Plugins are cleaned up
+	// once the network is closed.
+	n.Plugins.Each(func(plugin PluginInterface) {
+		plugin.Startup(n)
+	})
+
 	close(n.Listening)
 
-	glog.Infof("Listening for peers on %s.\n", n.Address)
+	// failed is closed should any listener fail, stopping all others.
+	failed := make(chan struct{})
+	var failOnce sync.Once
 
 	// handle server shutdowns
 	go func() {
 		select {
-		case <-n.kill:
-			// cause listener.Accept() to stop blocking so it can continue the loop
+		case <-n.closing:
+		case <-failed:
+		}
+
+		// cause listener.Accept() to stop blocking so it can continue the loop
+		for _, listener := range listeners {
 			listener.Close()
 		}
 	}()
 
+	errs := make(chan error, len(listeners))
+
+	for _, listener := range listeners {
+		go func(listener net.Listener) {
+			err := n.serve(listener, failed)
+			if err != nil {
+				failOnce.Do(func() {
+					close(failed)
+				})
+			}
+			errs <- err
+		}(listener)
+	}
+
+	var err error
+	for range listeners {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return err
+}
+
+// serve accepts peers through a listener until either the network is closed, or failed
+// is closed due to another listener failing.
+func (n *Network) serve(listener net.Listener, failed chan struct{}) error {
+	glog.Infof("Listening for peers on %s.\n", listener.Addr())
+
 	// Handle new clients.
 	for {
-		if conn, err := listener.Accept(); err == nil {
+		conn, err := listener.Accept()
+		if err == nil {
 			go n.Accept(conn)
+			continue
+		}
 
-		} else {
-			// if the Shutdown flag is set, no need to continue with the for loop
-			select {
-			case <-n.kill:
-				glog.Infof("Shutting down server on %s.\n", n.Address)
-				return
-			default:
-				glog.Error(err)
-			}
+		// if the Shutdown flag is set, no need to continue with the for loop
+		select {
+		case <-n.closing:
+			glog.Infof("Shutting down server on %s.\n", listener.Addr())
+			return nil
+		case <-failed:
+			return nil
+		default:
 		}
+
+		if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+			glog.Error(err)
+			continue
+		}
+
+		return errors.Wrapf(err, "network: failed to accept peers on %s", listener.Addr())
 	}
 }
 
+// Addresses returns all addresses the network listens on, starting with its primary address.
+func (n *Network) Addresses() []string {
+	return append([]string{n.Address}, n.ID.Addresses...)
+}
+
 // Client either creates or returns a cached peer client given its host address.
 func (n *Network) Client(address string) (*PeerClient, error) {
+	return n.ClientContext(context.Background(), address)
+}
+
+// ClientContext either creates or returns a cached peer client given its host address.
+// Connecting to the peer is aborted should ctx be done, or should it take longer than
+// the connection timeout.
+//
+// Peers which dialed us merely claim to reside at the address they advertise, so the
+// address is dialed to verify that the peer residing at it holds the same public key.
+func (n *Network) ClientContext(ctx context.Context, address string) (*PeerClient, error) {
+	return n.clientContext(ctx, address, nil)
+}
+
+// clientContext either creates or returns a cached peer client given its host address.
+// Should the ID of the peer be expected, a peer which dialed us over the address is
+// returned without verifying its address should it hold the public key expected.
+func (n *Network) clientContext(ctx context.Context, address string, expected *peer.ID) (*PeerClient, error) {
+	if n.opts.connectionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.opts.connectionTimeout)
+		defer cancel()
+	}
+
 	address, err := ToUnifiedAddress(address)
 	if err != nil {
 		return nil, err
@@ -249,36 +530,290 @@ func (n *Network) Client(address string) (*PeerClient, error) {
 	}
 
 	c, exists := n.Peers.LoadOrStore(address, clientNew)
+	client := c.(*PeerClient)
+
 	if exists {
-		client := c.(*PeerClient)
+		// Wait for whoever is connecting to the peer.
+		select {
+		case <-client.ready:
+		case <-client.settled:
+			if _, established := n.Connections.Load(address); !established {
+				return nil, errors.New("network: peer failed to connect")
+			}
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "network: peer failed to connect")
+		}
+
+		return n.verify(ctx, client, expected)
+	}
+
+	defer close(client.settled)
+
+	// Dialing the peer is aborted should the peer meanwhile connect to us instead, such
+	// that the connection we dialed is not left hanging halfway through its handshake.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	client.connMutex.Lock()
+	client.cancelDial = cancel
+	if _, established := n.Connections.Load(address); established {
+		cancel()
+	}
+	client.connMutex.Unlock()
+
+	conn, err := n.dialPeer(ctx, address)
+	if err != nil {
+		return n.abandon(ctx, client, err)
+	}
+
+	session, err := n.handshake(ctx, conn, true, false, nil)
+	if err != nil {
+		conn.Close()
+		return n.abandon(ctx, client, err)
+	}
+
+	// Only accept the peer residing at the address we dialed, so that it may not claim
+	// to be another node's entry in our peers and connections.
+	if session.id.Address != address {
+		session.conn.Close()
+		return n.abandon(ctx, client, errors.Errorf("network: peer dialed at %s advertised a different address %s", address, session.id.Address))
+	}
+
+	if err := n.establish(client, session, true); err != nil {
+		session.conn.Close()
 
-		if !client.OutgoingReady() {
-			return nil, errors.New("network: peer failed to connect")
+		// Only use the connection the peer dialed should it be the very peer residing at
+		// the address we dialed.
+		client, err := n.abandon(ctx, client, err)
+		if err != nil {
+			return nil, err
 		}
+		return n.confirm(ctx, client, session.id)
+	}
 
+	go n.receiveMessages(client, session)
+
+	return client, nil
+}
+
+// abandon gives up on connecting to a peer, failing with err. Should the peer meanwhile have
+// dialed us however, the connection it dialed is used instead.
+func (n *Network) abandon(ctx context.Context, client *PeerClient, err error) (*PeerClient, error) {
+	// Wait for connections dialed by the peer which are being accepted.
+	client.accepting.Wait(ctx)
+
+	client.connMutex.Lock()
+	defer client.connMutex.Unlock()
+
+	if _, established := n.Connections.Load(client.Address); established {
 		return client, nil
 	}
 
-	client := c.(*PeerClient)
-	defer func() {
-		close(client.outgoingReady)
-	}()
+	n.Peers.Delete(client.Address)
+
+	return nil, err
+}
+
+// verify returns the client of a peer once the peer is known to reside at the address of
+// the client. Peers which dialed us are verified by dialing their address, and checking
+// that the peer residing at it holds the same public key. Should the peer hold the public
+// key expected however, it is returned as is.
+func (n *Network) verify(ctx context.Context, client *PeerClient, expected *peer.ID) (*PeerClient, error) {
+	client.connMutex.Lock()
+	verified := client.verified || (expected != nil && client.ID.Equals(*expected))
+	client.connMutex.Unlock()
+
+	if verified {
+		return client, nil
+	}
 
-	conn, err := n.Dial(address)
+	conn, err := n.dialPeer(ctx, client.Address)
 	if err != nil {
-		n.Peers.Delete(address)
-		return nil, err
+		return nil, errors.Wrapf(err, "network: failed to verify peer %s resides at its address", client.Address)
+	}
+	defer conn.Close()
+
+	session, err := n.handshake(ctx, conn, true, true, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "network: failed to verify peer %s resides at its address", client.Address)
+	}
+
+	if session.id.Address != client.Address {
+		return nil, errors.Errorf("network: peer dialed at %s advertised a different address %s", client.Address, session.id.Address)
+	}
+
+	return n.confirm(ctx, client, session.id)
+}
+
+// confirm returns the client of a peer given the ID of the peer found residing at its
+// address. Should the peer connected to us hold another public key, it merely claimed the
+// address of another node. It is then disconnected, and the address is dialed instead.
+func (n *Network) confirm(ctx context.Context, client *PeerClient, id *peer.ID) (*PeerClient, error) {
+	client.connMutex.Lock()
+	claimed := client.ID
+	if claimed.Equals(*id) {
+		client.verified = true
+	}
+	client.connMutex.Unlock()
+
+	if claimed.Equals(*id) {
+		return client, nil
+	}
+
+	glog.Warningf("Peer %s connected as %s with a public key that does not belong to it.", claimed.PublicKeyHex(), client.Address)
+
+	client.Close()
+
+	return n.ClientContext(ctx, client.Address)
+}
+
+// establish makes the connection of a session the connection messages are exchanged with a
+// peer over, noting whether the peer was dialed at its address. Errors should the peer
+// already have a connection established, or should the client of the peer have been
+// abandoned or closed.
+func (n *Network) establish(client *PeerClient, session *session, verified bool) error {
+	client.connMutex.Lock()
+	defer client.connMutex.Unlock()
+
+	if c, exists := n.Peers.Load(client.Address); !exists || c != client || atomic.LoadUint32(&client.closed) == 1 {
+		return errors.Errorf("network: peer %s is no longer being connected to", client.Address)
+	}
+
+	if _, established := n.Connections.Load(client.Address); established {
+		return errPeerConnected
 	}
 
-	n.Connections.Store(address, &ConnState{
-		conn:        conn,
-		writer:      bufio.NewWriterSize(conn, n.opts.writeBufferSize),
+	client.ID = session.id
+	client.features = session.features
+	client.verified = verified
+
+	n.Connections.Store(client.Address, &ConnState{
+		conn:        session.conn,
+		writer:      bufio.NewWriterSize(session.conn, n.opts.writeBufferSize),
+		window:      NewSendWindow(n.opts.sendWindowSize),
+		scheduler:   newWriteScheduler(),
 		writerMutex: new(sync.Mutex),
+		macKey:      session.secrets.sendMAC,
+		compression: n.frameCompression(client, session),
+		features:    session.features,
 	})
 
 	client.Init()
+	close(client.ready)
 
-	return client, nil
+	if client.cancelDial != nil {
+		client.cancelDial()
+	}
+
+	return nil
+}
+
+// admit decides whether to accept a connection dialed by a peer once the peer has been
+// authenticated, returning the client of the peer the connection is to be established for,
+// and whether the client was created to do so. The client is marked as accepting the
+// connection, such that it is not abandoned meanwhile.
+//
+// Should both ends dial each other at once, the connection dialed by the node with the
+// lesser public key is kept. The node with the lesser public key therefore waits for its
+// own dial to the peer to settle, and turns the peer away should it succeed.
+func (n *Network) admit(ctx context.Context, id *peer.ID) (*PeerClient, bool, error) {
+	if id.Address == n.Address {
+		return nil, false, errors.Errorf("network: peer %s advertised our own address", id.PublicKeyHex())
+	}
+
+	clientNew, err := createPeerClient(n, id.Address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	c, exists := n.Peers.LoadOrStore(id.Address, clientNew)
+	client := c.(*PeerClient)
+
+	if exists && bytes.Compare(n.ID.PublicKey, id.PublicKey) < 0 {
+		select {
+		case <-client.ready:
+		case <-client.settled:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+
+	if _, established := n.Connections.Load(id.Address); established {
+		return nil, false, errPeerConnected
+	}
+
+	client.accepting.Add()
+
+	return client, !exists, nil
+}
+
+// ClientByID either creates or returns a cached peer client given its ID. Should the peer
+// advertise several addresses, it is dialed over the most preferred transport it may be
+// reached through. A peer which dialed us is returned without dialing its address should
+// it hold the public key of the ID.
+func (n *Network) ClientByID(id peer.ID) (*PeerClient, error) {
+	n.rememberAddresses(id)
+
+	return n.clientContext(context.Background(), id.Address, &id)
+}
+
+// rememberAddresses records the addresses advertised by a peer, such that it may be dialed
+// over any of them.
+func (n *Network) rememberAddresses(id peer.ID) {
+	if len(id.Addresses) == 0 {
+		return
+	}
+
+	n.addressBook.Store(id.Address, append([]string{id.Address}, id.Addresses...))
+}
+
+// dialAddresses returns the addresses a peer may be dialed at given its primary address,
+// ordered by transport preference. Addresses over transports which are not registered
+// are left out.
+func (n *Network) dialAddresses(address string) []string {
+	addresses := []string{address}
+	if known, exists := n.addressBook.Load(address); exists {
+		addresses = known.([]string)
+	}
+
+	var dialable []string
+	for _, candidate := range addresses {
+		if info, err := ParseAddress(candidate); err == nil {
+			if _, registered := n.Transports.Load(info.Protocol); registered {
+				dialable = append(dialable, candidate)
+			}
+		}
+	}
+
+	// Have dialing fail on the primary address should none of the addresses be dialable.
+	if len(dialable) == 0 {
+		return []string{address}
+	}
+
+	sortByTransportPreference(dialable, n.opts.transportPreference)
+
+	return dialable
+}
+
+// dialPeer dials a peer over the first of its addresses reachable, in order of transport
+// preference.
+func (n *Network) dialPeer(ctx context.Context, address string) (net.Conn, error) {
+	var err error
+
+	for _, candidate := range n.dialAddresses(address) {
+		var conn net.Conn
+		if conn, err = n.DialContext(ctx, candidate); err == nil {
+			return conn, nil
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		glog.Warningf("failed to dial %s at %s [err=%s]", address, candidate, err)
+	}
+
+	return nil, err
 }
 
 // BlockUntilListening blocks until this node is listening for new peers.
@@ -309,60 +844,151 @@ func (n *Network) Bootstrap(addresses ...string) {
 
 // Dial establishes a bidirectional connection to an address, and additionally handshakes with said address.
 func (n *Network) Dial(address string) (net.Conn, error) {
+	return n.DialContext(context.Background(), address)
+}
+
+// DialContext establishes a bidirectional connection to an address through its transport layer,
+// aborting should ctx be done, or should it take longer than the connection timeout.
+func (n *Network) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	if n.opts.connectionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.opts.connectionTimeout)
+		defer cancel()
+	}
+
 	addrInfo, err := ParseAddress(address)
 	if err != nil {
 		return nil, err
 	}
 
-	if addrInfo.Host != "127.0.0.1" {
+	// Peers sharing our host are dialed over the loopback interface, as our public
+	// address may not be reachable from within the host, such as when behind NAT.
+	if n.opts.loopbackRewrite {
 		host, err := ParseAddress(n.Address)
 		if err != nil {
 			return nil, err
 		}
-		// check if dialing address is same as its own IP
-		if addrInfo.Host == host.Host {
-			addrInfo.Host = "127.0.0.1"
+		if len(addrInfo.Host) > 0 && addrInfo.Host == host.Host {
+			addrInfo.Host = loopbackHost(addrInfo.Host)
 		}
 	}
 
 	// Choose scheme.
 	t, exists := n.Transports.Load(addrInfo.Protocol)
 	if !exists {
-		glog.Fatal("invalid protocol: " + addrInfo.Protocol)
+		return nil, errors.Errorf("network: no transport layer registered for protocol %q", addrInfo.Protocol)
 	}
 
 	var conn net.Conn
-	conn, err = t.(transport.Layer).Dial(addrInfo.HostPort())
+	conn, err = t.(transport.Layer).DialContext(ctx, addrInfo.TransportAddress())
 	if err != nil {
 		return nil, err
 	}
 	return conn, nil
 }
 
-// Accept handles peer registration and processes incoming message streams.
+// Accept handles peer registration and processes incoming message streams. The connection
+// is used to exchange messages with the peer both ways.
 func (n *Network) Accept(incoming net.Conn) {
-	var outgoing net.Conn
+	// Authenticate the peer before anything it sends is processed.
+	ctx := context.Background()
+	if n.opts.connectionTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.opts.connectionTimeout)
+		defer cancel()
+	}
 
-	var client *PeerClient
-	var clientInit sync.Once
+	var (
+		client  *PeerClient
+		created bool
+	)
 
-	// Cleanup connections when we are done with them.
-	defer func() {
-		if client != nil {
-			client.Close()
+	session, err := n.handshake(ctx, incoming, false, false, func(id *peer.ID) (err error) {
+		client, created, err = n.admit(ctx, id)
+		return err
+	})
+	if err == nil && session.probe {
+		// The peer only verified that we reside at our address.
+		session.conn.Close()
+		return
+	}
+	if err == nil {
+		// Remember the addresses of the peer, should we ever have to dial it ourselves.
+		n.rememberAddresses(*session.id)
+
+		err = n.establish(client, session, false)
+	}
+
+	if client != nil {
+		client.accepting.Done()
+
+		if created {
+			if err != nil {
+				n.abandon(ctx, client, err)
+			}
+			close(client.settled)
 		}
+	}
 
-		if incoming != nil {
+	if err != nil {
+		if session != nil {
+			session.conn.Close()
+		} else {
 			incoming.Close()
 		}
 
-		if outgoing != nil {
-			outgoing.Close()
+		if err == errPeerConnected {
+			glog.Info(err)
+		} else {
+			glog.Error(err)
+		}
+		return
+	}
+
+	n.receiveMessages(client, session)
+}
+
+// receiveMessages processes the messages a peer sends over the connection of a session
+// until the connection is closed, after which the peer is disconnected.
+func (n *Network) receiveMessages(client *PeerClient, session *session) {
+	incoming := session.conn
+
+	// Cleanup the connection when we are done with it.
+	defer func() {
+		client.Close()
+		incoming.Close()
+	}()
+
+	// Messages are numbered from 1 by the peer for every connection.
+	window := NewRecvWindow(n.opts.recvWindowSize)
+
+	compression := n.frameCompression(client, session)
+
+	// Messages too large for a single frame are reassembled out of their chunks.
+	chunks := newChunkReassembler(n.opts.hashPolicy, n.opts.maxMessageSize)
+
+	// Acknowledge received messages in the background, coalescing acknowledgements
+	// while messages keep on arriving.
+	var received uint64
+	acknowledge := make(chan struct{}, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			select {
+			case <-acknowledge:
+				if err := n.acknowledge(client.Address, atomic.LoadUint64(&received)); err != nil {
+					glog.Warning(err)
+				}
+			case <-done:
+				return
+			}
 		}
 	}()
 
 	for {
-		msg, err := n.receiveMessage(incoming)
+		msg, err := n.receiveMessage(incoming, session.secrets.recvMAC, compression)
 		if err != nil {
 			if err != errEmptyMsg {
 				glog.Error(err)
@@ -370,40 +996,91 @@ func (n *Network) Accept(incoming net.Conn) {
 			break
 		}
 
-		go func() {
-			// Initialize client if not exists.
-			clientInit.Do(func() {
-				client, err = n.Client(msg.Sender.Address)
-				if err != nil {
-					return
-				}
+		// Peer sent message with a completely different ID. Disconnect.
+		if !client.ID.Equals(peer.ID(*msg.Sender)) || client.ID.Address != msg.Sender.Address {
+			glog.Errorf("message signed by peer %s but client is %s", peer.ID(*msg.Sender), client.ID.Address)
+			break
+		}
 
-				client.ID = (*peer.ID)(msg.Sender)
+		typ, _ := n.opts.codec.Type(msg.Message)
 
-				// Load an outgoing connection.
-				if state, established := n.Connections.Load(client.ID.Address); established {
-					outgoing = state.(*ConnState).conn
-				} else {
-					err = errors.New("network: failed to load session")
-				}
+		// Acknowledgements are not numbered, and release our own send window.
+		if typ == ackType {
+			payload, err := n.opts.codec.Decode(msg.Message)
+			if err != nil {
+				glog.Error(err)
+				continue
+			}
+			ack := payload.(*protobuf.Ack)
 
-				// Signal that the client is ready.
-				close(client.incomingReady)
-			})
+			if state, exists := n.Connections.Load(client.Address); exists {
+				state.(*ConnState).window.Ack(ack.Nonce)
+			}
+			continue
+		}
+
+		// Peers shutting down are sent no more new messages, though they are still sent
+		// replies to their requests.
+		if typ == goodbyeType {
+			atomic.StoreUint32(&client.shuttingDown, 1)
+			continue
+		}
 
+		// Reject replayed messages, and hold back messages received ahead of others.
+		if err := window.Input(msg.MessageNonce, msg); err != nil {
+			glog.Errorf("network: dropped message from %s: %v", client.ID.Address, err)
+			continue
+		}
+
+		ready := window.Update()
+		for _, ready := range ready {
+			msg := ready.(*protobuf.Message)
+
+			payload, err := n.opts.codec.Decode(msg.Message)
 			if err != nil {
-				glog.Error(err)
-				return
+				glog.Errorf("network: dropped message from %s: %v", client.ID.Address, err)
+				continue
 			}
 
-			// Peer sent message with a completely different ID. Disconnect.
-			if !client.ID.Equals(peer.ID(*msg.Sender)) {
-				glog.Errorf("message signed by peer %s but client is %s", peer.ID(*msg.Sender), client.ID.Address)
-				return
+			if chunk, ok := payload.(*protobuf.Chunk); ok {
+				if msg, err = chunks.Input(msg, chunk); err != nil {
+					glog.Errorf("network: dropped message from %s: %v", client.ID.Address, err)
+				}
+				if msg == nil {
+					continue
+				}
+
+				if payload, err = n.opts.codec.Decode(msg.Message); err != nil {
+					glog.Errorf("network: dropped message from %s: %v", client.ID.Address, err)
+					continue
+				}
 			}
 
-			client.Submit(func() { n.dispatchMessage(client, msg) })
-		}()
+			client.Submit(func() { n.dispatchMessage(client, msg, payload) })
+		}
+
+		if len(ready) > 0 {
+			atomic.AddUint64(&received, uint64(len(ready)))
+
+			select {
+			case acknowledge <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// frameCompression returns the compression of messages exchanged with a peer over the
+// connection of a session, or nil should no compression algorithm have been negotiated.
+func (n *Network) frameCompression(client *PeerClient, session *session) *frameCompression {
+	if session.compressor == nil {
+		return nil
+	}
+
+	return &frameCompression{
+		compressor: session.compressor,
+		threshold:  n.opts.compressionThreshold,
+		counters:   &client.compression,
 	}
 }
 
@@ -415,50 +1092,165 @@ func (n *Network) Plugin(key interface{}) (PluginInterface, bool) {
 	return n.Plugins.Get(key)
 }
 
-// PrepareMessage marshals a message into a *protobuf.Message and signs it with this
-// nodes private key. Errors if the message is null.
+// PrepareMessage marshals a message into a *protobuf.Message. Errors if the message is null.
+//
+// The message is left unsigned, and is instead signed with this nodes private key, or
+// authenticated with a session MAC under AuthSessionMAC, for each connection it is written
+// to, such that the nonces it is written with are covered too.
 func (n *Network) PrepareMessage(message proto.Message) (*protobuf.Message, error) {
-	if message == nil {
-		return nil, errors.New("network: message is null")
-	}
+	return n.wrapMessage(n.opts.codec, message)
+}
 
-	raw, err := types.MarshalAny(message)
+// signMessage encodes a message with a codec into a *protobuf.Message and signs it with
+// this nodes private key.
+func (n *Network) signMessage(codec Codec, message proto.Message) (*protobuf.Message, error) {
+	msg, err := n.wrapMessage(codec, message)
 	if err != nil {
 		return nil, err
 	}
 
-	id := protobuf.ID(n.ID)
+	if err := n.sign(msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
 
-	signature, err := n.keys.Sign(
+// sign signs a message, alongside its nonces and flags, with this nodes private key.
+func (n *Network) sign(msg *protobuf.Message) (err error) {
+	msg.Signature, err = n.keys.Sign(
 		n.opts.signaturePolicy,
 		n.opts.hashPolicy,
-		SerializeMessage(&id, raw.Value),
+		serializeAuthenticated(msg),
 	)
+	return err
+}
+
+// wrapMessage encodes a message with a codec into an unsigned *protobuf.Message sent by
+// this node.
+func (n *Network) wrapMessage(codec Codec, message proto.Message) (*protobuf.Message, error) {
+	if message == nil {
+		return nil, errors.New("network: message is null")
+	}
+
+	raw, err := codec.Encode(message)
 	if err != nil {
 		return nil, err
 	}
 
+	id := protobuf.ID(n.ID)
+
 	msg := &protobuf.Message{
-		Message:   raw,
-		Sender:    &id,
-		Signature: signature,
+		Message: raw,
+		Sender:  &id,
 	}
 	return msg, nil
 }
 
 // Write asynchronously sends a message to a denoted target address.
+//
+// Should the peer have too many messages left unacknowledged, the configured
+// backpressure policy is applied: Write either blocks until the peer catches up,
+// fails with ErrSendWindowFull, or drops the message and returns nil.
 func (n *Network) Write(address string, message *protobuf.Message) error {
+	return n.WriteContext(context.Background(), address, message)
+}
+
+// WriteContext asynchronously sends a message to a denoted target address. Should ctx
+// be done before the message is written, the message is not sent.
+func (n *Network) WriteContext(ctx context.Context, address string, message *protobuf.Message) error {
+	return n.writeContext(ctx, address, message, n.opts.backpressure)
+}
+
+// writeContext sends a message to a denoted target address, applying a backpressure policy
+// should the peer have too many messages left unacknowledged.
+func (n *Network) writeContext(ctx context.Context, address string, message *protobuf.Message, policy BackpressurePolicy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	s, exists := n.Connections.Load(address)
 	if !exists {
 		return errors.New("network: connection does not exist")
 	}
 	state := s.(*ConnState)
 
-	message.MessageNonce = atomic.AddUint64(&state.messageNonce, 1)
+	if n.opts.writeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.opts.writeTimeout)
+		defer cancel()
+	}
+
+	priority := n.priorityOf(ctx, message)
+
+	// Messages too large to be sent in a single frame are sent in chunks.
+	if message.Size() > maxUnchunkedSize {
+		if !state.features.Has(FeatureChunking) {
+			return errors.Errorf("network: message of %d bytes is too large to be sent to %s, which does not support chunking", message.Size(), address)
+		}
+		return n.writeChunked(ctx, state, address, message, policy, priority)
+	}
+
+	err := state.window.Reserve(ctx, policy)
+	if err == errMessageDropped {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to write to %s", address)
+	}
+
+	return n.write(state, message, priority, true)
+}
+
+// acknowledge tells the peer at address that all messages it has sent up to and
+// including nonce were received. Acknowledgements bypass the send window and are
+// flushed immediately.
+func (n *Network) acknowledge(address string, nonce uint64) error {
+	s, exists := n.Connections.Load(address)
+	if !exists {
+		return errors.New("network: connection does not exist")
+	}
+	state := s.(*ConnState)
+
+	message, err := n.PrepareMessage(&protobuf.Ack{Nonce: nonce})
+	if err != nil {
+		return err
+	}
+
+	if err := n.write(state, message, PriorityControl, false); err != nil {
+		return err
+	}
+
+	state.writerMutex.Lock()
+	defer state.writerMutex.Unlock()
+
+	return state.writer.Flush()
+}
+
+// write sends a message over a connection once its turn comes amongst the messages of
+// other classes queued up. Numbered messages are assigned the next nonce of the connection
+// as they are written, and must have had room reserved in its send window.
+func (n *Network) write(state *ConnState, message *protobuf.Message, priority Priority, numbered bool) error {
+	// Copy the message, as it may be written to several connections.
+	msg := *message
+
+	state.scheduler.Acquire(priority)
+	defer state.scheduler.Release()
+
+	if numbered {
+		msg.MessageNonce = state.window.Next()
+	}
+
+	// Authenticate the message alongside the nonces it is written with.
+	if n.opts.authMode == AuthSessionMAC {
+		msg.Mac = messageMAC(state.macKey, &msg)
+	} else if err := n.sign(&msg); err != nil {
+		return err
+	}
 
 	state.conn.SetWriteDeadline(time.Now().Add(n.opts.writeTimeout))
 
-	err := n.sendMessage(state.writer, message, state.writerMutex)
+	err := n.sendMessage(state.writer, &msg, state.writerMutex, state.compression)
 	if err != nil {
 		return err
 	}
@@ -469,6 +1261,9 @@ func (n *Network) Write(address string, message *protobuf.Message) error {
 func (n *Network) Broadcast(message proto.Message) {
 	n.Peers.Range(func(key, value interface{}) bool {
 		client := value.(*PeerClient)
+		if client.ShuttingDown() {
+			return true
+		}
 
 		err := client.Tell(message)
 		if err != nil {
@@ -528,15 +1323,3 @@ func (n *Network) BroadcastRandomly(message proto.Message, K int) {
 
 	n.BroadcastByAddresses(message, addresses[:K]...)
 }
-
-// Close shuts down the entire network.
-func (n *Network) Close() {
-	// Kill the listener.
-	close(n.kill)
-
-	// Clean out client connections.
-	n.Peers.Range(func(key, value interface{}) bool {
-		value.(*PeerClient).Close()
-		return true
-	})
-}
@@ -1,6 +1,7 @@
 package network
 
 import (
+	"context"
 	"net"
 
 	"github.com/gogo/protobuf/proto"
@@ -17,12 +18,28 @@ type NetworkInterface interface {
 	// GetKeys() returns the keypair for this network
 	GetKeys() *crypto.KeyPair
 
-	// Listen starts listening for peers on a port.
-	Listen()
+	// Listen starts listening for peers on all addresses of the network, and serves them
+	// until the network is closed.
+	Listen() error
+
+	// Serve serves peers connecting through a number of listeners until the network is closed.
+	Serve(listeners ...net.Listener) error
+
+	// Addresses returns all addresses the network listens on, starting with its primary address.
+	Addresses() []string
 
 	// Client either creates or returns a cached peer client given its host address.
 	Client(address string) (*PeerClient, error)
 
+	// ClientContext either creates or returns a cached peer client given its host address.
+	// Connecting to the peer is aborted should ctx be done, or should it take longer than
+	// the connection timeout.
+	ClientContext(ctx context.Context, address string) (*PeerClient, error)
+
+	// ClientByID either creates or returns a cached peer client given its ID, dialing it over
+	// the most preferred transport among the addresses it advertises.
+	ClientByID(id peer.ID) (*PeerClient, error)
+
 	// BlockUntilListening blocks until this node is listening for new peers.
 	BlockUntilListening()
 
@@ -32,6 +49,10 @@ type NetworkInterface interface {
 	// Dial establishes a bidirectional connection to an address, and additionally handshakes with said address.
 	Dial(address string) (net.Conn, error)
 
+	// DialContext establishes a bidirectional connection to an address through its transport layer,
+	// aborting should ctx be done, or should it take longer than the connection timeout.
+	DialContext(ctx context.Context, address string) (net.Conn, error)
+
 	// Accept handles peer registration and processes incoming message streams.
 	Accept(conn net.Conn)
 
@@ -41,13 +62,17 @@ type NetworkInterface interface {
 	// Example: network.Plugin((*Plugin)(nil))
 	Plugin(key interface{}) (PluginInterface, bool)
 
-	// PrepareMessage marshals a message into a *protobuf.Message and signs it with this
-	// nodes private key. Errors if the message is null.
+	// PrepareMessage marshals a message into a *protobuf.Message, which is signed or
+	// authenticated as it is written to each connection. Errors if the message is null.
 	PrepareMessage(message proto.Message) (*protobuf.Message, error)
 
 	// Write asynchronously sends a message to a denoted target address.
 	Write(address string, message *protobuf.Message) error
 
+	// WriteContext asynchronously sends a message to a denoted target address. Should ctx
+	// be done before the message is written, the message is not sent.
+	WriteContext(ctx context.Context, address string, message *protobuf.Message) error
+
 	// Broadcast asynchronously broadcasts a message to all peer clients.
 	Broadcast(message proto.Message)
 
@@ -61,6 +86,10 @@ type NetworkInterface interface {
 	// Does not guarantee broadcasting to exactly K peers.
 	BroadcastRandomly(message proto.Message, K int)
 
-	// Close shuts down the entire network.
+	// Shutdown gracefully shuts down the entire network, waiting for handlers and
+	// requests in flight to finish.
+	Shutdown(ctx context.Context) error
+
+	// Close immediately shuts down the entire network.
 	Close()
 }
@@ -0,0 +1,472 @@
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/perlin-network/noise/network/transport"
+	"github.com/perlin-network/noise/protobuf"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+func TestListenUnknownProtocol(t *testing.T) {
+	t.Parallel()
+
+	builder := NewBuilder()
+	builder.SetAddress(fmt.Sprintf("quic://%s:%d", host, 12031))
+
+	n, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := n.Listen(); err == nil {
+		t.Error("expected listening over an unknown protocol to fail")
+	}
+
+	if _, err := n.Dial(fmt.Sprintf("quic://%s:%d", host, 12032)); err == nil {
+		t.Error("expected dialing over an unknown protocol to fail")
+	}
+}
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, 12033))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice := buildHandshakeNetwork(t, 12033)
+	bob := buildHandshakeNetwork(t, 12034)
+	defer bob.Close()
+
+	go bob.Listen()
+	bob.BlockUntilListening()
+
+	served := make(chan error, 1)
+	go func() {
+		served <- alice.Serve(listener)
+	}()
+	alice.BlockUntilListening()
+
+	if _, err := bob.Client(alice.Address); err != nil {
+		t.Fatalf("expected peer to connect through the listener served: %+v", err)
+	}
+
+	second, err := net.Listen("tcp", fmt.Sprintf("%s:%d", host, 12035))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := alice.Serve(second); err == nil {
+		t.Error("expected serving peers twice to fail")
+	}
+
+	alice.Close()
+
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("expected Serve to return no error once closed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected Serve to return once closed")
+	}
+}
+
+func TestListenMultipleAddresses(t *testing.T) {
+	t.Parallel()
+
+	builder := NewBuilder()
+	builder.SetAddress(fmt.Sprintf("%s://%s:%d", protocol, host, 12036))
+	builder.AddAddress(fmt.Sprintf("kcp://%s:%d", host, 12037))
+
+	alice, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer alice.Close()
+
+	if addresses := alice.Addresses(); len(addresses) != 2 {
+		t.Fatalf("expected the network to have two addresses, got %v", addresses)
+	}
+
+	bob := buildHandshakeNetwork(t, 12038, TransportPreference("kcp"))
+	defer bob.Close()
+
+	go alice.Listen()
+	go bob.Listen()
+	alice.BlockUntilListening()
+	bob.BlockUntilListening()
+
+	if _, err := bob.ClientByID(alice.ID); err != nil {
+		t.Fatalf("expected peer to connect over its preferred transport: %+v", err)
+	}
+
+	state, established := bob.Connections.Load(alice.Address)
+	if !established {
+		t.Fatal("expected connection to be keyed by the primary address of the peer")
+	}
+	if remote := state.(*ConnState).conn.RemoteAddr().String(); remote != fmt.Sprintf("127.0.0.1:%d", 12037) {
+		t.Errorf("expected peer to be dialed over kcp, got %s", remote)
+	}
+}
+
+func TestListenIPv6(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetworkAt(t, fmt.Sprintf("%s://[::1]:%d", protocol, 12039), ListenHost("::1"))
+	defer alice.Close()
+
+	bob := buildHandshakeNetworkAt(t, fmt.Sprintf("%s://[::1]:%d", protocol, 12040), ListenHost("::1"))
+	defer bob.Close()
+
+	go alice.Listen()
+	go bob.Listen()
+	alice.BlockUntilListening()
+	bob.BlockUntilListening()
+
+	client, err := bob.Client(alice.Address)
+	if err != nil {
+		t.Fatalf("expected peer to connect over IPv6: %+v", err)
+	}
+
+	if client.ID.Address != fmt.Sprintf("%s://[::1]:%d", protocol, 12039) {
+		t.Errorf("expected peer to be identified by its IPv6 address, got %s", client.ID.Address)
+	}
+}
+
+func TestListenTLS(t *testing.T) {
+	t.Parallel()
+
+	build := func(port uint16) *Network {
+		keys := ed25519.RandomKeyPair()
+
+		layer, err := transport.NewTLSWithKeys(keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		builder := NewBuilder()
+		builder.SetKeys(keys)
+		builder.SetAddress(fmt.Sprintf("tls://%s:%d", host, port))
+		builder.RegisterTransportLayer("tls", layer)
+
+		n, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	alice, bob := build(12041), build(12042)
+	defer alice.Close()
+	defer bob.Close()
+
+	go alice.Listen()
+	go bob.Listen()
+	alice.BlockUntilListening()
+	bob.BlockUntilListening()
+
+	client, err := bob.Client(alice.Address)
+	if err != nil {
+		t.Fatalf("expected peer to connect over TLS: %+v", err)
+	}
+
+	state, _ := bob.Connections.Load(alice.Address)
+	if publicKey, derived := transport.PeerPublicKey(state.(*ConnState).conn); !derived || !bytes.Equal(publicKey, client.ID.PublicKey) {
+		t.Errorf("expected the TLS identity of the peer to match its ID, got %x", publicKey)
+	}
+}
+
+// sameConnection returns true should alice and bob be connected to each other over a
+// single connection.
+func sameConnection(alice, bob *Network) bool {
+	a, established := alice.Connections.Load(bob.Address)
+	if !established {
+		return false
+	}
+	b, established := bob.Connections.Load(alice.Address)
+	if !established {
+		return false
+	}
+	return a.(*ConnState).conn.LocalAddr().String() == b.(*ConnState).conn.RemoteAddr().String()
+}
+
+func TestSingleConnection(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12043)
+	defer alice.Close()
+
+	// Bob does not listen for peers, as though he were behind NAT.
+	bob := buildHandshakeNetwork(t, 12044)
+	defer bob.Close()
+
+	go alice.Listen()
+	alice.BlockUntilListening()
+
+	if _, err := bob.Client(alice.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	// Alice reaches bob over the connection he dialed.
+	client, err := alice.ClientByID(bob.ID)
+	if err != nil {
+		t.Fatalf("expected peer which dialed us to be reachable without dialing it back: %+v", err)
+	}
+	if !client.ID.Equals(bob.ID) {
+		t.Errorf("expected peer to be identified as %s, got %s", bob.ID, client.ID)
+	}
+
+	if !sameConnection(alice, bob) {
+		t.Error("expected peers to exchange messages over a single connection")
+	}
+
+	// Bob's address may not be verified without dialing it.
+	if _, err := alice.Client(bob.Address); err == nil {
+		t.Error("expected the unverifiable address of a peer which dialed us to be refused")
+	}
+}
+
+func TestVerifyAddress(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12053)
+	defer alice.Close()
+
+	bob := buildHandshakeNetwork(t, 12054)
+	defer bob.Close()
+
+	honest := buildHandshakeNetwork(t, 12055)
+	defer honest.Close()
+
+	// Mallory claims the address of honest.
+	mallory := buildHandshakeNetworkAt(t, honest.Address)
+	defer mallory.Close()
+
+	go alice.Listen()
+	go bob.Listen()
+	go honest.Listen()
+	alice.BlockUntilListening()
+	bob.BlockUntilListening()
+	honest.BlockUntilListening()
+
+	if _, err := bob.Client(alice.Address); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mallory.Client(alice.Address); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatalf("expected address of peer which dialed us to be verified: %+v", err)
+	}
+	if !client.ID.Equals(bob.ID) {
+		t.Errorf("expected peer to be identified as %s, got %s", bob.ID, client.ID)
+	}
+	if !sameConnection(alice, bob) {
+		t.Error("expected verifying the address of a peer to keep its connection")
+	}
+
+	client, err = alice.Client(honest.Address)
+	if err != nil {
+		t.Fatalf("expected peer residing at its address to be connected to: %+v", err)
+	}
+	if !client.ID.Equals(honest.ID) {
+		t.Errorf("expected peer claiming the address of another to be replaced by %s, got %s", honest.ID, client.ID)
+	}
+}
+
+func TestSimultaneousDial(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12045)
+	defer alice.Close()
+
+	bob := buildHandshakeNetwork(t, 12046)
+	defer bob.Close()
+
+	go alice.Listen()
+	go bob.Listen()
+	alice.BlockUntilListening()
+	bob.BlockUntilListening()
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := alice.Client(bob.Address)
+		errs <- err
+	}()
+	go func() {
+		_, err := bob.Client(alice.Address)
+		errs <- err
+	}()
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("expected peers dialing each other at once to connect: %+v", err)
+		}
+	}
+
+	if !sameConnection(alice, bob) {
+		t.Error("expected peers dialing each other at once to settle on a single connection")
+	}
+}
+
+func TestFaultyTransport(t *testing.T) {
+	t.Parallel()
+
+	faults := transport.NewFaultInjector()
+
+	build := func(port uint16) *Network {
+		address := fmt.Sprintf("memory://127.0.0.1:%d", port)
+
+		builder := NewBuilder()
+		builder.SetAddress(address)
+		builder.RegisterTransportLayer("memory", faults.Wrap(transport.NewMemory(), address))
+
+		n, err := builder.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	alice, bob := build(12047), build(12048)
+	defer alice.Close()
+	defer bob.Close()
+
+	go alice.Listen()
+	go bob.Listen()
+	alice.BlockUntilListening()
+	bob.BlockUntilListening()
+
+	faults.Partition([]string{alice.Address}, []string{bob.Address})
+
+	if _, err := bob.Client(alice.Address); err == nil {
+		t.Fatal("expected peers across a partition to fail to connect")
+	}
+
+	faults.Heal()
+
+	if _, err := bob.Client(alice.Address); err != nil {
+		t.Fatalf("expected peers to connect once the partition is healed: %+v", err)
+	}
+}
+
+func TestListenPathAddresses(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "noise")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		alice, bob string
+	}{
+		{"unix://" + filepath.Join(dir, "alice.sock"), "unix://" + filepath.Join(dir, "bob.sock")},
+		{fmt.Sprintf("ws://%s:%d/noise", host, 12049), fmt.Sprintf("ws://%s:%d/noise", host, 12050)},
+	}
+	for _, tt := range testCases {
+		alice := buildHandshakeNetworkAt(t, tt.alice)
+		defer alice.Close()
+
+		bob := buildHandshakeNetworkAt(t, tt.bob)
+		defer bob.Close()
+
+		go alice.Listen()
+		go bob.Listen()
+		alice.BlockUntilListening()
+		bob.BlockUntilListening()
+
+		client, err := bob.Client(alice.Address)
+		if err != nil {
+			t.Fatalf("expected peer to connect over %s: %+v", alice.Address, err)
+		}
+		if !client.ID.Equals(alice.ID) {
+			t.Errorf("expected peer to be identified as %s, got %s", alice.ID, client.ID)
+		}
+	}
+}
+
+// replyFailPlugin replies to the pings it receives, only to fail them afterwards.
+type replyFailPlugin struct {
+	*Plugin
+}
+
+func (p *replyFailPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Ping); ok {
+		if err := ctx.Reply(&protobuf.Pong{}); err != nil {
+			return err
+		}
+		return errors.New("failed to handle ping after replying")
+	}
+	return nil
+}
+
+func TestPluginErrorAfterReply(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12056)
+	defer alice.Close()
+
+	builder := NewBuilder()
+	builder.SetKeys(ed25519.RandomKeyPair())
+	builder.SetAddress(fmt.Sprintf("%s://%s:%d", protocol, host, 12057))
+	builder.AddPlugin(new(replyFailPlugin))
+
+	bob, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bob.Close()
+
+	go bob.Listen()
+	bob.BlockUntilListening()
+
+	client, err := alice.Client(bob.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Track the request such that any reply beyond the first is caught.
+	replies := make(chan proto.Message, 2)
+	client.Requests.Store(uint64(1), &RequestState{data: replies, closeSignal: make(chan struct{})})
+
+	msg, err := alice.PrepareMessage(&protobuf.Ping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg.RequestNonce = 1
+
+	if err := alice.Write(bob.Address, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case res := <-replies:
+		if _, ok := res.(*protobuf.Pong); !ok {
+			t.Fatalf("expected the plugin's reply, got %v", res)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the plugin to reply")
+	}
+
+	select {
+	case res := <-replies:
+		t.Errorf("expected no error to be replied after the plugin's reply, got %v", res)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -0,0 +1,142 @@
+package network
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/perlin-network/noise/protobuf"
+)
+
+// Priority denotes the class of a message, which determines how soon the message is
+// written to a peer relative to other messages queued up to be written to the same peer.
+type Priority int
+
+const (
+	// PriorityControl is the class of small latency-sensitive messages, such as pings,
+	// peer lookups and acknowledgements.
+	PriorityControl Priority = iota
+
+	// PriorityRequest is the class of requests, responses and of all messages which
+	// were not assigned a class.
+	PriorityRequest
+
+	// PriorityBulk is the class of bulk transfers, such as raw bytes, stream data and
+	// chunks of large messages.
+	PriorityBulk
+
+	numPriorities
+)
+
+// priorityWeights denotes how many frames of each class are written in a row whenever
+// frames of several classes are queued up.
+var priorityWeights = [numPriorities]int{16, 4, 1}
+
+// defaultPriorities assigns classes to the messages of noise itself.
+var defaultPriorities = map[reflect.Type]Priority{
+	reflect.TypeOf(new(protobuf.Ping)):               PriorityControl,
+	reflect.TypeOf(new(protobuf.Pong)):               PriorityControl,
+	reflect.TypeOf(new(protobuf.LookupNodeRequest)):  PriorityControl,
+	reflect.TypeOf(new(protobuf.LookupNodeResponse)): PriorityControl,
+	reflect.TypeOf(new(protobuf.Ack)):                PriorityControl,
+	reflect.TypeOf(new(protobuf.Goodbye)):            PriorityControl,
+	reflect.TypeOf(new(protobuf.Bytes)):              PriorityBulk,
+	reflect.TypeOf(new(protobuf.Chunk)):              PriorityBulk,
+	reflect.TypeOf(new(protobuf.StreamFrame)):        PriorityBulk,
+}
+
+type priorityContextKey struct{}
+
+// WithPriority returns a copy of ctx which assigns a class to the messages written with
+// it, overriding the class registered for their type.
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, priority)
+}
+
+// priorityOf returns the class of a message written with ctx.
+func (n *Network) priorityOf(ctx context.Context, message *protobuf.Message) Priority {
+	if priority, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return priority
+	}
+
+	typ, err := n.opts.codec.Type(message.Message)
+	if err != nil {
+		return PriorityRequest
+	}
+
+	if priority, exists := n.opts.priorities[typ]; exists {
+		return priority
+	}
+
+	if priority, exists := defaultPriorities[typ]; exists {
+		return priority
+	}
+
+	return PriorityRequest
+}
+
+// writeScheduler takes turns amongst the frames queued up to be written to a connection,
+// interleaving frames of different classes by weighted round-robin.
+type writeScheduler struct {
+	sync.Mutex
+
+	busy    bool
+	waiting [numPriorities][]chan struct{}
+	credits [numPriorities]int
+}
+
+func newWriteScheduler() *writeScheduler {
+	return &writeScheduler{credits: priorityWeights}
+}
+
+// Acquire waits for the turn of a frame of a given class to be written.
+func (s *writeScheduler) Acquire(priority Priority) {
+	s.Lock()
+
+	if !s.busy {
+		s.busy = true
+		s.Unlock()
+		return
+	}
+
+	turn := make(chan struct{})
+	s.waiting[priority] = append(s.waiting[priority], turn)
+	s.Unlock()
+
+	<-turn
+}
+
+// Release ends the turn of the frame written, passing it on to the next frame.
+func (s *writeScheduler) Release() {
+	s.Lock()
+	defer s.Unlock()
+
+	priority, ok := s.next()
+	if !ok {
+		s.busy = false
+		return
+	}
+
+	turn := s.waiting[priority][0]
+	s.waiting[priority] = s.waiting[priority][1:]
+
+	close(turn)
+}
+
+// next picks the class of the next frame to be written. The second returning parameter
+// is false should no frames be queued up.
+func (s *writeScheduler) next() (Priority, bool) {
+	for round := 0; round < 2; round++ {
+		for priority := range s.waiting {
+			if len(s.waiting[priority]) > 0 && s.credits[priority] > 0 {
+				s.credits[priority]--
+				return Priority(priority), true
+			}
+		}
+
+		// All classes with frames queued up spent their turns.
+		s.credits = priorityWeights
+	}
+
+	return 0, false
+}
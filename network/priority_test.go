@@ -0,0 +1,83 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func TestWriteSchedulerOrder(t *testing.T) {
+	t.Parallel()
+
+	s := newWriteScheduler()
+	s.Acquire(PriorityBulk)
+
+	queued := []Priority{PriorityBulk, PriorityBulk, PriorityBulk, PriorityRequest, PriorityControl}
+	written := make(chan Priority, len(queued))
+
+	for i, priority := range queued {
+		go func(priority Priority) {
+			s.Acquire(priority)
+			written <- priority
+			s.Release()
+		}(priority)
+
+		// Wait for the frame to be queued up.
+		for {
+			s.Lock()
+			count := 0
+			for _, waiting := range s.waiting {
+				count += len(waiting)
+			}
+			s.Unlock()
+
+			if count == i+1 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	s.Release()
+
+	expected := []Priority{PriorityControl, PriorityRequest, PriorityBulk, PriorityBulk, PriorityBulk}
+	for i, priority := range expected {
+		if actual := <-written; actual != priority {
+			t.Fatalf("expected frame %d to be of class %d, got %d", i, priority, actual)
+		}
+	}
+}
+
+func TestPriorityOf(t *testing.T) {
+	t.Parallel()
+
+	n := &Network{opts: defaultBuilderOptions}
+	MessagePriority(new(protobuf.Pong), PriorityBulk)(&n.opts)
+
+	message := func(msg proto.Message) *protobuf.Message {
+		raw, err := types.MarshalAny(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &protobuf.Message{Message: raw}
+	}
+
+	ctx := context.Background()
+
+	if priority := n.priorityOf(ctx, message(&protobuf.Ping{})); priority != PriorityControl {
+		t.Errorf("expected pings to be control messages, got %d", priority)
+	}
+	if priority := n.priorityOf(ctx, message(&protobuf.Pong{})); priority != PriorityBulk {
+		t.Errorf("expected registered class to override the default, got %d", priority)
+	}
+	if priority := n.priorityOf(ctx, message(&protobuf.Error{})); priority != PriorityRequest {
+		t.Errorf("expected unregistered messages to be of the request class, got %d", priority)
+	}
+	if priority := n.priorityOf(WithPriority(ctx, PriorityControl), message(&protobuf.Bytes{})); priority != PriorityControl {
+		t.Errorf("expected class of context to override the registered class, got %d", priority)
+	}
+}
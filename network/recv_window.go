@@ -53,17 +53,27 @@ func (w *RecvWindow) Update() []interface{} {
 	return ready
 }
 
-// Input places a new received message into the receive buffer.
+// Input places a new received message into the receive buffer. Errors if the
+// message has already been received, or if its nonce falls outside the window.
 func (w *RecvWindow) Input(nonce uint64, msg interface{}) error {
 	w.Lock()
 	defer w.Unlock()
 
-	offset := int(nonce - w.localNonce)
+	if nonce < w.localNonce {
+		return errors.Errorf("Local nonce is %d while received %d which was already delivered", w.localNonce, nonce)
+	}
+
+	offset := nonce - w.localNonce
 
-	if offset < 0 || offset >= w.size {
+	if offset >= uint64(w.size) {
 		return errors.Errorf("Local nonce is %d while received %d", w.localNonce, nonce)
 	}
 
-	*w.buffer.Index(offset) = msg
+	cursor := w.buffer.Index(int(offset))
+	if *cursor != nil {
+		return errors.Errorf("Received nonce %d more than once", nonce)
+	}
+
+	*cursor = msg
 	return nil
 }
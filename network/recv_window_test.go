@@ -0,0 +1,81 @@
+package network
+
+import (
+	"testing"
+)
+
+func TestRecvWindowInOrder(t *testing.T) {
+	t.Parallel()
+
+	w := NewRecvWindow(4)
+
+	for nonce := uint64(1); nonce <= 10; nonce++ {
+		if err := w.Input(nonce, nonce); err != nil {
+			t.Fatal(err)
+		}
+
+		ready := w.Update()
+		if len(ready) != 1 || ready[0].(uint64) != nonce {
+			t.Fatalf("expected message %d to be ready, got %v", nonce, ready)
+		}
+	}
+}
+
+func TestRecvWindowReorder(t *testing.T) {
+	t.Parallel()
+
+	w := NewRecvWindow(4)
+
+	for _, nonce := range []uint64{3, 2} {
+		if err := w.Input(nonce, nonce); err != nil {
+			t.Fatal(err)
+		}
+		if ready := w.Update(); len(ready) != 0 {
+			t.Fatalf("expected no messages to be ready before message 1, got %v", ready)
+		}
+	}
+
+	if err := w.Input(1, uint64(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	ready := w.Update()
+	if len(ready) != 3 {
+		t.Fatalf("expected 3 messages to be ready, got %v", ready)
+	}
+	for i, msg := range ready {
+		if msg.(uint64) != uint64(i+1) {
+			t.Fatalf("expected messages in nonce order, got %v", ready)
+		}
+	}
+}
+
+func TestRecvWindowReplay(t *testing.T) {
+	t.Parallel()
+
+	w := NewRecvWindow(4)
+
+	if err := w.Input(1, 1); err != nil {
+		t.Fatal(err)
+	}
+	w.Update()
+
+	if err := w.Input(1, 1); err == nil {
+		t.Error("expected an already delivered message to be rejected")
+	}
+
+	if err := w.Input(3, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Input(3, 3); err == nil {
+		t.Error("expected a buffered message to be rejected when received twice")
+	}
+
+	if err := w.Input(0, 0); err == nil {
+		t.Error("expected a message without a nonce to be rejected")
+	}
+
+	if err := w.Input(6, 6); err == nil {
+		t.Error("expected a message beyond the window to be rejected")
+	}
+}
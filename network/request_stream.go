@@ -0,0 +1,462 @@
+package network
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/perlin-network/noise/network/rpc"
+	"github.com/perlin-network/noise/protobuf"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+)
+
+// requestStreamBufferSize is the number of messages buffered per request stream. Either end
+// of a stream may only send as many messages as the other end has room left to buffer.
+const requestStreamBufferSize = 16
+
+var (
+	// ErrStreamSendClosed is returned when sending over a request stream after CloseSend.
+	ErrStreamSendClosed = errors.New("network: request stream is closed for sending")
+
+	// ErrStreamClosed is returned when using a stream which was closed or cancelled.
+	ErrStreamClosed = errors.New("network: stream is closed")
+
+	// ErrStreamOverflow is returned by request streams the peer sent more responses over
+	// than it was granted credit for.
+	ErrStreamOverflow = errors.New("network: peer overflowed the request stream")
+)
+
+// streamCredit counts the messages which may be sent over a request stream before the
+// receiving end grants more, such that the messages of a stream which is not read from
+// never pile up.
+type streamCredit struct {
+	sync.Mutex
+	available int
+
+	// <-granted blocks until more credit is granted.
+	granted chan struct{}
+}
+
+// newStreamCredit creates credit for as many messages as the receiving end buffers.
+func newStreamCredit() *streamCredit {
+	return &streamCredit{available: requestStreamBufferSize, granted: make(chan struct{}, 1)}
+}
+
+// acquire waits for credit to send a message, failing should ctx be done first.
+func (c *streamCredit) acquire(ctx context.Context) error {
+	for {
+		c.Lock()
+		if c.available > 0 {
+			c.available--
+			if c.available > 0 {
+				c.signal()
+			}
+			c.Unlock()
+			return nil
+		}
+		c.Unlock()
+
+		select {
+		case <-c.granted:
+		case <-ctx.Done():
+			return ErrStreamClosed
+		}
+	}
+}
+
+// grant grants credit to send count more messages.
+func (c *streamCredit) grant(count uint32) {
+	c.Lock()
+	c.available += int(count)
+	c.signal()
+	c.Unlock()
+}
+
+// signal wakes up a sender waiting for credit.
+func (c *streamCredit) signal() {
+	select {
+	case c.granted <- struct{}{}:
+	default:
+	}
+}
+
+// streamConsumption counts the messages read from a request stream, granting credit back
+// to the sending end once half of its buffer was read.
+type streamConsumption struct {
+	client *PeerClient
+	nonce  uint64
+	reply  bool
+
+	consumed uint32
+}
+
+// consume counts a message read from the stream.
+func (c *streamConsumption) consume(ctx context.Context) {
+	c.consumed++
+
+	if c.consumed < requestStreamBufferSize/2 {
+		return
+	}
+
+	if err := c.client.writeFrame(ctx, c.nonce, c.reply, &protobuf.StreamCredit{Count: c.consumed}); err != nil {
+		glog.Warningf("failed to grant credit to request stream with %s [err=%s]", c.client.Address, err)
+	}
+
+	c.consumed = 0
+}
+
+// RequestStream is the requesting side of a request stream opened with a peer, over which
+// any number of requests and responses may be exchanged under a single request nonce.
+type RequestStream struct {
+	client *PeerClient
+	nonce  uint64
+	state  *RequestState
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Credit to send requests, and responses read to grant credit back for.
+	credit   *streamCredit
+	consumed streamConsumption
+
+	sendClosed  uint32 // for atomic ops
+	ended       uint32 // for atomic ops
+	overflowed  uint32 // for atomic ops
+	closeOnce   sync.Once
+	releaseOnce sync.Once
+
+	// err is the error Recv returns once the peer has ended the stream.
+	err error
+}
+
+// OpenRequestStream opens a request stream with a peer with a first request, which the
+// peer handles with the stream handler registered for its type. The stream is cancelled
+// should ctx be done before the stream ends.
+//
+// Responses are received with Recv until it returns io.EOF, or an *rpc.Error should the
+// peer fail to handle the stream. Close must be called once the stream is no longer used.
+func (c *PeerClient) OpenRequestStream(ctx context.Context, req proto.Message) (*RequestStream, error) {
+	if c.ShuttingDown() {
+		return nil, ErrPeerShuttingDown
+	}
+	if err := c.requireFeature(FeatureRequestStreams); err != nil {
+		return nil, err
+	}
+
+	message, err := c.Network.opts.codec.Encode(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal request")
+	}
+
+	s := &RequestStream{
+		client: c,
+		nonce:  atomic.AddUint64(&c.RequestNonce, 1),
+		credit: newStreamCredit(),
+	}
+	s.consumed = streamConsumption{client: c, nonce: s.nonce}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	// Room is left for the peer to end the stream once it used up all of its credit.
+	s.state = &RequestState{
+		data:        make(chan proto.Message, requestStreamBufferSize+1),
+		closeSignal: make(chan struct{}),
+		stream:      s,
+	}
+
+	// Start tracking the stream before it is opened, so that no response may be missed.
+	c.Requests.Store(s.nonce, s.state)
+
+	if err := c.writeFrame(s.ctx, s.nonce, false, &protobuf.StreamOpen{Message: message}); err != nil {
+		s.release()
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			s.Close()
+		case <-s.state.closeSignal:
+		}
+	}()
+
+	return s, nil
+}
+
+// Send sends a request over the stream.
+func (s *RequestStream) Send(req proto.Message) error {
+	if atomic.LoadUint32(&s.sendClosed) == 1 {
+		return ErrStreamSendClosed
+	}
+
+	if s.ctx.Err() != nil {
+		return ErrStreamClosed
+	}
+
+	if err := s.credit.acquire(s.ctx); err != nil {
+		return err
+	}
+
+	return s.client.writeFrame(s.ctx, s.nonce, false, req)
+}
+
+// CloseSend signals to the peer that no more requests are to be sent over the stream.
+func (s *RequestStream) CloseSend() error {
+	if !atomic.CompareAndSwapUint32(&s.sendClosed, 0, 1) {
+		return nil
+	}
+
+	return s.client.writeFrame(s.ctx, s.nonce, false, &protobuf.StreamEnd{})
+}
+
+// Recv receives the next response sent over the stream. It returns io.EOF once the peer
+// is done sending responses, or an *rpc.Error should the peer fail to handle the stream.
+func (s *RequestStream) Recv() (proto.Message, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	select {
+	case res := <-s.state.data:
+		switch res := res.(type) {
+		case *protobuf.StreamEnd:
+			s.err = io.EOF
+		case *protobuf.Error:
+			s.err = s.client.Network.remoteError(res)
+		default:
+			s.consumed.consume(s.ctx)
+			return res, nil
+		}
+
+		atomic.StoreUint32(&s.ended, 1)
+		s.release()
+		return nil, s.err
+	case <-s.ctx.Done():
+		if atomic.LoadUint32(&s.overflowed) == 1 {
+			return nil, ErrStreamOverflow
+		}
+		return nil, ErrStreamClosed
+	}
+}
+
+// dispatch hands a message sent by the peer over the stream to Recv. Should the peer send
+// more responses than it was granted credit for, the stream is cancelled rather than
+// holding up the messages of the peer which are yet to be dispatched.
+func (s *RequestStream) dispatch(msg proto.Message) {
+	if credit, ok := msg.(*protobuf.StreamCredit); ok {
+		s.credit.grant(credit.Count)
+		return
+	}
+
+	select {
+	case s.state.data <- msg:
+	default:
+		atomic.StoreUint32(&s.overflowed, 1)
+		s.cancel()
+	}
+}
+
+// Close closes the stream, cancelling it should the peer have yet to end it.
+func (s *RequestStream) Close() error {
+	var err error
+
+	s.closeOnce.Do(func() {
+		if atomic.LoadUint32(&s.ended) == 0 {
+			err = s.client.writeFrame(context.Background(), s.nonce, false, &protobuf.StreamCancel{})
+		}
+		s.release()
+	})
+
+	return err
+}
+
+// release stops tracking the stream.
+func (s *RequestStream) release() {
+	s.releaseOnce.Do(func() {
+		s.client.Requests.Delete(s.nonce)
+		s.cancel()
+		close(s.state.closeSignal)
+	})
+}
+
+// serverStream is the handling side of a request stream opened by a peer.
+type serverStream struct {
+	client *PeerClient
+	nonce  uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	incoming chan proto.Message
+	recvDone bool
+
+	// Credit to send responses, and requests read to grant credit back for.
+	credit   *streamCredit
+	consumed streamConsumption
+}
+
+// Context implements rpc.Stream.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// Send implements rpc.Stream.
+func (s *serverStream) Send(res proto.Message) error {
+	if s.ctx.Err() != nil {
+		return ErrStreamClosed
+	}
+
+	if err := s.credit.acquire(s.ctx); err != nil {
+		return err
+	}
+
+	return s.client.writeFrame(s.ctx, s.nonce, true, res)
+}
+
+// Recv implements rpc.Stream.
+func (s *serverStream) Recv() (proto.Message, error) {
+	if s.recvDone {
+		return nil, io.EOF
+	}
+
+	select {
+	case req := <-s.incoming:
+		if _, ok := req.(*protobuf.StreamEnd); ok {
+			s.recvDone = true
+			return nil, io.EOF
+		}
+		s.consumed.consume(s.ctx)
+		return req, nil
+	case <-s.ctx.Done():
+		return nil, ErrStreamClosed
+	}
+}
+
+// dispatchStreamFrame dispatches a message sent by a peer under a request nonce to the
+// request stream it belongs to. It returns false should the message not belong to a stream.
+func (n *Network) dispatchStreamFrame(client *PeerClient, nonce uint64, msg proto.Message) bool {
+	if open, ok := msg.(*protobuf.StreamOpen); ok {
+		n.openServerStream(client, nonce, open)
+		return true
+	}
+
+	_stream, exists := client.requestStreams.Load(nonce)
+	if !exists {
+		// Frames of streams which already ended are dropped.
+		switch msg.(type) {
+		case *protobuf.StreamEnd, *protobuf.StreamCancel, *protobuf.StreamCredit:
+			return true
+		}
+		return false
+	}
+
+	stream := _stream.(*serverStream)
+
+	switch msg := msg.(type) {
+	case *protobuf.StreamCancel:
+		stream.cancel()
+		return true
+	case *protobuf.StreamCredit:
+		stream.credit.grant(msg.Count)
+		return true
+	}
+
+	if stream.ctx.Err() != nil {
+		return true
+	}
+
+	// Should the peer send more requests than it was granted credit for, the stream is
+	// failed rather than holding up the messages of the peer which are yet to be dispatched.
+	select {
+	case stream.incoming <- msg:
+	default:
+		stream.cancel()
+		n.replyError(client, nonce, rpc.Errorf(rpc.CodeInvalidRequest, "request stream %d overflowed with more than %d requests", nonce, requestStreamBufferSize))
+	}
+
+	return true
+}
+
+// openServerStream runs the stream handler registered for the first request of a stream.
+func (n *Network) openServerStream(client *PeerClient, nonce uint64, open *protobuf.StreamOpen) {
+	req, err := n.opts.codec.Decode(open.Message)
+	if err != nil {
+		n.replyError(client, nonce, rpc.Errorf(rpc.CodeInvalidRequest, "failed to decode request: %v", err))
+		return
+	}
+
+	var handler rpc.StreamHandler
+	exists := false
+
+	if n.opts.service != nil {
+		handler, exists = n.opts.service.LookupStream(req)
+	}
+
+	if !exists {
+		n.replyError(client, nonce, rpc.Errorf(rpc.CodeUnimplemented, "no stream handler for %T", req))
+		return
+	}
+
+	stream := &serverStream{
+		client:   client,
+		nonce:    nonce,
+		incoming: make(chan proto.Message, requestStreamBufferSize+1),
+		credit:   newStreamCredit(),
+		consumed: streamConsumption{client: client, nonce: nonce, reply: true},
+	}
+	stream.ctx, stream.cancel = context.WithCancel(WithClient(context.Background(), client))
+
+	if _, loaded := client.requestStreams.LoadOrStore(nonce, stream); loaded {
+		glog.Warningf("peer %s opened request stream %d twice", client.Address, nonce)
+		return
+	}
+
+	n.inflight.Add()
+	go func() {
+		defer n.inflight.Done()
+
+		err := handler(stream.ctx, req, stream)
+
+		client.requestStreams.Delete(nonce)
+
+		// Nothing is to be sent over streams cancelled by the requester.
+		if stream.ctx.Err() == nil {
+			if err != nil {
+				n.replyError(client, nonce, err)
+			} else if err := client.writeFrame(stream.ctx, nonce, true, &protobuf.StreamEnd{}); err != nil {
+				glog.Warningf("failed to end request stream with %s [err=%s]", client.Address, err)
+			}
+		}
+
+		stream.cancel()
+	}()
+}
+
+// closeRequestStreams cancels all request streams opened by the peer.
+func (c *PeerClient) closeRequestStreams() {
+	c.requestStreams.Range(func(nonce, stream interface{}) bool {
+		stream.(*serverStream).cancel()
+		c.requestStreams.Delete(nonce)
+		return true
+	})
+}
+
+// writeFrame writes a message belonging to the request or request stream numbered nonce.
+func (c *PeerClient) writeFrame(ctx context.Context, nonce uint64, reply bool, message proto.Message) error {
+	signed, err := c.Network.PrepareMessage(message)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign message")
+	}
+
+	signed.RequestNonce = nonce
+	signed.ReplyFlag = reply
+
+	if err := c.Network.WriteContext(ctx, c.Address, signed); err != nil {
+		return errors.Wrapf(err, "failed to send message to %s", c.Address)
+	}
+
+	return nil
+}
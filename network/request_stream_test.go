@@ -0,0 +1,67 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/protobuf"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+func TestStreamCredit(t *testing.T) {
+	t.Parallel()
+
+	credit := newStreamCredit()
+
+	for i := 0; i < requestStreamBufferSize; i++ {
+		if err := credit.acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := credit.acquire(ctx); err != ErrStreamClosed {
+		t.Fatalf("acquire() = %v, expected to wait for credit until cancelled", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- credit.acquire(context.Background())
+	}()
+
+	credit.grant(1)
+
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected credit granted to wake up the sender")
+	}
+}
+
+func TestRequestStreamOverflow(t *testing.T) {
+	t.Parallel()
+
+	s := &RequestStream{credit: newStreamCredit()}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.state = &RequestState{data: make(chan proto.Message, requestStreamBufferSize+1), stream: s}
+
+	for i := 0; i < requestStreamBufferSize+1; i++ {
+		s.dispatch(&protobuf.Ping{})
+	}
+	if s.ctx.Err() != nil {
+		t.Fatal("expected the responses the stream buffers to be dispatched")
+	}
+
+	// Dispatching never blocks, should the peer overflow the stream.
+	s.dispatch(&protobuf.Ping{})
+	if s.ctx.Err() == nil {
+		t.Fatal("expected the stream to be cancelled once overflowed")
+	}
+}
@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// Codes categorizing why a request failed. Applications may define their own codes
+// starting from CodeUser.
+const (
+	// CodeUnknown is the code of requests which failed with an error other than an *Error.
+	CodeUnknown uint32 = iota
+
+	// CodeInvalidRequest denotes a request which was malformed or otherwise rejected.
+	CodeInvalidRequest
+
+	// CodeInternal denotes a request which failed due to an internal error of the remote peer.
+	CodeInternal
+
+	// CodeUnimplemented denotes a request which the remote peer has no handler for.
+	CodeUnimplemented
+
+	// CodeUser is the first code free to be used by applications.
+	CodeUser uint32 = 1000
+)
+
+// Error is returned by requests which the remote peer failed to handle.
+//
+// Handlers and plugins may return an *Error themselves to have its code and details
+// forwarded to the requesting peer. Any other error is forwarded with CodeUnknown.
+type Error struct {
+	Code    uint32
+	Message string
+
+	// Details optionally hold a message describing the failure further.
+	Details proto.Message
+}
+
+// Errorf creates a new error with a code and a formatted message.
+func Errorf(code uint32, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc: request failed with code %d: %s", e.Code, e.Message)
+}
+
+// AsError returns err as an *Error, unwrapping errors wrapped by github.com/pkg/errors.
+// Errors of any other type are converted into an *Error of CodeUnknown.
+func AsError(err error) *Error {
+	if rpcErr, ok := errors.Cause(err).(*Error); ok {
+		return rpcErr
+	}
+	return &Error{Code: CodeUnknown, Message: err.Error()}
+}
+
+// Normalize returns the error a handler or plugin returned, or nil should it have returned a
+// nil *Error, which does not compare equal to a nil error.
+func Normalize(err error) error {
+	if rpcErr, ok := errors.Cause(err).(*Error); ok && rpcErr == nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestAsError(t *testing.T) {
+	t.Parallel()
+
+	err := Errorf(CodeInvalidRequest, "bad request %d", 1)
+	if AsError(errors.Wrap(err, "wrapped")) != err {
+		t.Error("expected a wrapped *Error to be unwrapped")
+	}
+
+	converted := AsError(errors.New("plain error"))
+	if converted.Code != CodeUnknown || converted.Message != "plain error" {
+		t.Errorf("expected a plain error to be converted with CodeUnknown, got %v", converted)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	var typedNil *Error
+	if err := Normalize(typedNil); err != nil {
+		t.Errorf("Normalize() = %v, expected a nil *Error to be no error", err)
+	}
+
+	if err := Normalize(nil); err != nil {
+		t.Errorf("Normalize() = %v, expected <nil>", err)
+	}
+
+	expected := errors.New("failed to handle request")
+	if err := Normalize(expected); err != expected {
+		t.Errorf("Normalize() = %v, expected %v", err, expected)
+	}
+}
@@ -10,6 +10,10 @@ import (
 // a response designated by a timeout.
 type Request struct {
 	Message proto.Message
+
+	// Timeout bounds how long a response is waited upon. Requests with no timeout
+	// wait until the context they are made with is done, or time out after a default
+	// timeout should they be made without a context.
 	Timeout time.Duration
 }
 
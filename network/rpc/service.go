@@ -0,0 +1,162 @@
+package rpc
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	messageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+	streamType  = reflect.TypeOf((*Stream)(nil)).Elem()
+
+	// DefaultService is the service handlers registered through Handle are registered onto.
+	DefaultService = NewService()
+)
+
+// Handler handles a single request, returning either a response or an error which is
+// replied back to the requesting peer.
+type Handler func(ctx context.Context, req proto.Message) (proto.Message, error)
+
+// StreamHandler handles a request stream opened by a peer with its first request, until
+// it returns. Returning an error fails the stream.
+type StreamHandler func(ctx context.Context, req proto.Message, stream Stream) error
+
+// Service routes incoming requests and request streams to handlers registered by the
+// type of request.
+type Service struct {
+	sync.RWMutex
+	handlers       map[reflect.Type]Handler
+	streamHandlers map[reflect.Type]StreamHandler
+}
+
+// NewService creates a new service with no handlers.
+func NewService() *Service {
+	return &Service{
+		handlers:       make(map[reflect.Type]Handler),
+		streamHandlers: make(map[reflect.Type]StreamHandler),
+	}
+}
+
+// Handle registers a handler onto the default service.
+func Handle(handler interface{}) error {
+	return DefaultService.Handle(handler)
+}
+
+// HandleStream registers a stream handler onto the default service.
+func HandleStream(handler interface{}) error {
+	return DefaultService.HandleStream(handler)
+}
+
+// Handle registers a handler for a single type of request. The handler must be a function
+// of the form
+//
+//	func(ctx context.Context, req *Request) (*Response, error)
+//
+// where *Request and *Response are protobuf messages. Errors if a handler for the same type
+// of request was already registered.
+func (s *Service) Handle(handler interface{}) error {
+	if handler == nil {
+		return errors.New("rpc: handler must not be nil")
+	}
+
+	fn := reflect.ValueOf(handler)
+	typ := fn.Type()
+
+	if typ.Kind() != reflect.Func || typ.NumIn() != 2 || typ.NumOut() != 2 ||
+		typ.In(0) != contextType || !typ.In(1).Implements(messageType) ||
+		!typ.Out(0).Implements(messageType) || typ.Out(1) != errorType {
+		return errors.Errorf("rpc: handler must be of the form func(context.Context, proto.Message) (proto.Message, error), got %s", typ)
+	}
+
+	reqType := typ.In(1)
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, exists := s.handlers[reqType]; exists {
+		return errors.Errorf("rpc: a handler for %s is already registered", reqType)
+	}
+
+	s.handlers[reqType] = func(ctx context.Context, req proto.Message) (proto.Message, error) {
+		results := fn.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(req)})
+
+		if err, _ := results[1].Interface().(error); Normalize(err) != nil {
+			return nil, err
+		}
+
+		res, _ := results[0].Interface().(proto.Message)
+		if res == nil || results[0].IsNil() {
+			return nil, errors.Errorf("rpc: handler for %s returned no response", reqType)
+		}
+
+		return res, nil
+	}
+
+	return nil
+}
+
+// Lookup returns the handler registered for the type of a request. The second returning
+// parameter is false should no handler be registered.
+func (s *Service) Lookup(req proto.Message) (Handler, bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	handler, exists := s.handlers[reflect.TypeOf(req)]
+	return handler, exists
+}
+
+// HandleStream registers a handler for request streams opened with a single type of request.
+// The handler must be a function of the form
+//
+//	func(ctx context.Context, req *Request, stream rpc.Stream) error
+//
+// where *Request is a protobuf message. Errors if a stream handler for the same type of
+// request was already registered.
+func (s *Service) HandleStream(handler interface{}) error {
+	if handler == nil {
+		return errors.New("rpc: stream handler must not be nil")
+	}
+
+	fn := reflect.ValueOf(handler)
+	typ := fn.Type()
+
+	if typ.Kind() != reflect.Func || typ.NumIn() != 3 || typ.NumOut() != 1 ||
+		typ.In(0) != contextType || !typ.In(1).Implements(messageType) ||
+		typ.In(2) != streamType || typ.Out(0) != errorType {
+		return errors.Errorf("rpc: stream handler must be of the form func(context.Context, proto.Message, rpc.Stream) error, got %s", typ)
+	}
+
+	reqType := typ.In(1)
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, exists := s.streamHandlers[reqType]; exists {
+		return errors.Errorf("rpc: a stream handler for %s is already registered", reqType)
+	}
+
+	s.streamHandlers[reqType] = func(ctx context.Context, req proto.Message, stream Stream) error {
+		results := fn.Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(req), reflect.ValueOf(&stream).Elem()})
+
+		err, _ := results[0].Interface().(error)
+		return err
+	}
+
+	return nil
+}
+
+// LookupStream returns the stream handler registered for the type of a request. The second
+// returning parameter is false should no stream handler be registered.
+func (s *Service) LookupStream(req proto.Message) (StreamHandler, bool) {
+	s.RLock()
+	defer s.RUnlock()
+
+	handler, exists := s.streamHandlers[reflect.TypeOf(req)]
+	return handler, exists
+}
@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func pingHandler(ctx context.Context, req *protobuf.Ping) (*protobuf.Pong, error) {
+	return &protobuf.Pong{}, nil
+}
+
+func TestServiceHandle(t *testing.T) {
+	t.Parallel()
+
+	s := NewService()
+
+	if err := s.Handle(pingHandler); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Handle(pingHandler); err == nil {
+		t.Error("expected a second handler for the same request type to be rejected")
+	}
+
+	handler, exists := s.Lookup(&protobuf.Ping{})
+	if !exists {
+		t.Fatal("expected handler for ping to be registered")
+	}
+
+	res, err := handler(context.Background(), &protobuf.Ping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.(*protobuf.Pong); !ok {
+		t.Errorf("expected a pong, got %T", res)
+	}
+
+	if _, exists := s.Lookup(&protobuf.Pong{}); exists {
+		t.Error("expected no handler for pong to be registered")
+	}
+}
+
+func TestServiceHandleInvalid(t *testing.T) {
+	t.Parallel()
+
+	invalid := []interface{}{
+		nil,
+		"not a function",
+		func(req *protobuf.Ping) (*protobuf.Pong, error) { return nil, nil },
+		func(ctx context.Context, req string) (*protobuf.Pong, error) { return nil, nil },
+		func(ctx context.Context, req *protobuf.Ping) *protobuf.Pong { return nil },
+		func(ctx context.Context, req *protobuf.Ping) (*protobuf.Pong, string) { return nil, "" },
+	}
+
+	s := NewService()
+
+	for _, handler := range invalid {
+		if err := s.Handle(handler); err == nil {
+			t.Errorf("expected handler of type %T to be rejected", handler)
+		}
+	}
+}
+
+func TestServiceNoResponse(t *testing.T) {
+	t.Parallel()
+
+	s := NewService()
+
+	err := s.Handle(func(ctx context.Context, req *protobuf.Ping) (*protobuf.Pong, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler, _ := s.Lookup(&protobuf.Ping{})
+	if _, err := handler(context.Background(), &protobuf.Ping{}); err == nil {
+		t.Error("expected a handler returning no response to error")
+	}
+}
+
+func TestServiceNilError(t *testing.T) {
+	t.Parallel()
+
+	s := NewService()
+
+	err := s.Handle(func(ctx context.Context, req *protobuf.Ping) (*protobuf.Pong, error) {
+		var err *Error
+		return &protobuf.Pong{}, err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler, _ := s.Lookup(&protobuf.Ping{})
+	if res, err := handler(context.Background(), &protobuf.Ping{}); err != nil || res == nil {
+		t.Errorf("handler() = (%v, %v), expected a nil *Error to be no error", res, err)
+	}
+}
+
+func TestServiceHandleStream(t *testing.T) {
+	t.Parallel()
+
+	s := NewService()
+
+	handler := func(ctx context.Context, req *protobuf.Ping, stream Stream) error {
+		return nil
+	}
+
+	if err := s.HandleStream(handler); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.HandleStream(handler); err == nil {
+		t.Error("expected a second stream handler for the same request type to be rejected")
+	}
+
+	if err := s.HandleStream(pingHandler); err == nil {
+		t.Error("expected a unary handler to be rejected as a stream handler")
+	}
+
+	if _, exists := s.LookupStream(&protobuf.Ping{}); !exists {
+		t.Error("expected stream handler for ping to be registered")
+	}
+
+	if _, exists := s.Lookup(&protobuf.Ping{}); exists {
+		t.Error("expected no unary handler for ping to be registered")
+	}
+}
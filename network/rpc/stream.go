@@ -0,0 +1,22 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// Stream is the handling side of a request stream opened by a peer, over which any
+// number of requests and responses may be exchanged.
+type Stream interface {
+	// Context returns the context of the stream, which is done once the peer cancels
+	// the stream or disconnects.
+	Context() context.Context
+
+	// Send sends a response to the peer.
+	Send(res proto.Message) error
+
+	// Recv receives the next request sent by the peer, returning io.EOF once the peer
+	// is done sending requests.
+	Recv() (proto.Message, error)
+}
@@ -0,0 +1,140 @@
+package network
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrSendWindowFull is returned when writing to a peer which has too many
+	// unacknowledged messages, under BackpressureFail or once BackpressureBlock
+	// times out.
+	ErrSendWindowFull = errors.New("network: send window is full")
+
+	// errMessageDropped is returned by SendWindow.Reserve when a message should be
+	// dropped under BackpressureDrop.
+	errMessageDropped = errors.New("network: message dropped as send window is full")
+)
+
+// BackpressurePolicy denotes what happens to a message written to a peer which
+// has too many unacknowledged messages.
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock blocks the writer until the peer acknowledges enough
+	// messages, or until the write timeout elapses.
+	BackpressureBlock BackpressurePolicy = iota
+
+	// BackpressureFail immediately fails the write with ErrSendWindowFull.
+	BackpressureFail
+
+	// BackpressureDrop silently drops the message.
+	BackpressureDrop
+)
+
+// SendWindow tracks messages sent over a connection which have yet to be acknowledged
+// by the receiving peer, bounding how many of them may be outstanding at once.
+//
+// Room for a message is reserved before it is queued up to be written, while its nonce
+// is only assigned once it is written, such that messages are numbered in the order
+// they are sent in regardless of their priority.
+type SendWindow struct {
+	sync.Mutex
+
+	size     int
+	nonce    uint64
+	acked    uint64
+	reserved uint64
+
+	// signal is closed and replaced whenever new messages are acknowledged.
+	signal chan struct{}
+}
+
+// NewSendWindow creates a new send window allowing up to size unacknowledged messages.
+func NewSendWindow(size int) *SendWindow {
+	return &SendWindow{
+		size:   size,
+		signal: make(chan struct{}),
+	}
+}
+
+// Reserve reserves room for a message to be sent, applying the given backpressure policy
+// should the window be full. Every successful reservation must be followed by a call to
+// Next once the message is written.
+//
+// Under BackpressureBlock, Reserve gives up with ErrSendWindowFull once ctx reaches its
+// deadline, or with the context's error should it be cancelled.
+func (w *SendWindow) Reserve(ctx context.Context, policy BackpressurePolicy) error {
+	w.Lock()
+	for w.size > 0 && w.nonce-w.acked+w.reserved >= uint64(w.size) {
+		switch policy {
+		case BackpressureFail:
+			w.Unlock()
+			return ErrSendWindowFull
+		case BackpressureDrop:
+			w.Unlock()
+			return errMessageDropped
+		}
+
+		signal := w.signal
+		w.Unlock()
+
+		select {
+		case <-signal:
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return ErrSendWindowFull
+			}
+			return ctx.Err()
+		}
+
+		w.Lock()
+	}
+
+	w.reserved++
+	w.Unlock()
+
+	return nil
+}
+
+// Next assigns the nonce of the next message written, out of a previous reservation.
+// Nonces are only assigned to messages that are sent, such that the peer receives them
+// without gaps.
+func (w *SendWindow) Next() uint64 {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.reserved > 0 {
+		w.reserved--
+	}
+
+	w.nonce++
+	return w.nonce
+}
+
+// Ack marks all messages up to and including nonce as acknowledged by the peer.
+func (w *SendWindow) Ack(nonce uint64) {
+	w.Lock()
+	defer w.Unlock()
+
+	// Acknowledgements may arrive late, or be for messages never sent.
+	if nonce <= w.acked || nonce > w.nonce {
+		return
+	}
+
+	w.acked = nonce
+
+	close(w.signal)
+	w.signal = make(chan struct{})
+}
+
+// Outstanding returns the number of messages reserved or sent which have yet to be
+// acknowledged.
+func (w *SendWindow) Outstanding() int {
+	w.Lock()
+	defer w.Unlock()
+
+	return int(w.nonce - w.acked + w.reserved)
+}
@@ -0,0 +1,120 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func fillSendWindow(t *testing.T, w *SendWindow, count int) {
+	for i := 1; i <= count; i++ {
+		if err := w.Reserve(context.Background(), BackpressureFail); err != nil {
+			t.Fatal(err)
+		}
+		if nonce := w.Next(); nonce != uint64(i) {
+			t.Fatalf("expected nonce %d, got %d", i, nonce)
+		}
+	}
+}
+
+func TestSendWindowPolicies(t *testing.T) {
+	t.Parallel()
+
+	w := NewSendWindow(2)
+	fillSendWindow(t, w, 2)
+
+	if err := w.Reserve(context.Background(), BackpressureFail); err != ErrSendWindowFull {
+		t.Errorf("expected %v, got %v", ErrSendWindowFull, err)
+	}
+
+	if err := w.Reserve(context.Background(), BackpressureDrop); err != errMessageDropped {
+		t.Errorf("expected %v, got %v", errMessageDropped, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := w.Reserve(ctx, BackpressureBlock); err != ErrSendWindowFull {
+		t.Errorf("expected blocking reservation to time out with %v, got %v", ErrSendWindowFull, err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	if err := w.Reserve(ctx, BackpressureBlock); err != context.Canceled {
+		t.Errorf("expected cancelled reservation to fail with %v, got %v", context.Canceled, err)
+	}
+
+	// Failed reservations must not consume nonces.
+	w.Ack(1)
+
+	if err := w.Reserve(context.Background(), BackpressureFail); err != nil {
+		t.Fatal(err)
+	}
+	if nonce := w.Next(); nonce != 3 {
+		t.Errorf("expected nonce 3, got %d", nonce)
+	}
+}
+
+func TestSendWindowBlockUntilAck(t *testing.T) {
+	t.Parallel()
+
+	w := NewSendWindow(1)
+	fillSendWindow(t, w, 1)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		w.Ack(1)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+
+	if err := w.Reserve(ctx, BackpressureBlock); err != nil {
+		t.Fatal(err)
+	}
+	if nonce := w.Next(); nonce != 2 {
+		t.Errorf("expected nonce 2, got %d", nonce)
+	}
+	if outstanding := w.Outstanding(); outstanding != 1 {
+		t.Errorf("expected 1 outstanding message, got %d", outstanding)
+	}
+}
+
+func TestSendWindowStaleAck(t *testing.T) {
+	t.Parallel()
+
+	w := NewSendWindow(4)
+	fillSendWindow(t, w, 3)
+
+	w.Ack(2)
+	w.Ack(1)
+	w.Ack(10)
+
+	if outstanding := w.Outstanding(); outstanding != 1 {
+		t.Errorf("expected stale and bogus acknowledgements to be ignored, got %d outstanding", outstanding)
+	}
+}
+
+func TestSendWindowUnbounded(t *testing.T) {
+	t.Parallel()
+
+	w := NewSendWindow(0)
+	fillSendWindow(t, w, 100)
+}
+
+func TestSendWindowReservedCountsTowardsSize(t *testing.T) {
+	t.Parallel()
+
+	w := NewSendWindow(2)
+
+	for i := 0; i < 2; i++ {
+		if err := w.Reserve(context.Background(), BackpressureFail); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Reserve(context.Background(), BackpressureFail); err != ErrSendWindowFull {
+		t.Errorf("expected reservations yet to be written to fill the window, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package network
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/protobuf"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// sessionKeySize is the size of ephemeral X25519 keys and derived session keys.
+	sessionKeySize = 32
+
+	// maxSessionFrameSize is the maximum amount of plaintext sealed into a single frame.
+	maxSessionFrameSize = 16 * 1024
+
+	sessionKeyInfo = "noise session keys"
+)
+
+var errInvalidEphemeralKey = errors.New("session: peer sent an invalid ephemeral key")
+
+// AuthMode denotes how outgoing messages are authenticated.
+type AuthMode int
+
+const (
+	// AuthSignature signs every message with the node's private key, such that the
+	// message may be relayed and verified by any peer.
+	AuthSignature AuthMode = iota
+
+	// AuthSessionMAC authenticates every message with a MAC keyed from the session
+	// established during the handshake. It is far cheaper than signing, though messages
+	// may then only be verified by the peer they were sent to.
+	AuthSessionMAC
+)
+
+// session is the outcome of a handshake over a connection.
+type session struct {
+	conn    net.Conn
+	id      *peer.ID
+	secrets *sessionSecrets
+
+	// Compression algorithm negotiated for the connection, or nil should there be none.
+	compressor Compressor
+
+	// Protocol version and features negotiated with the peer.
+	features *Features
+
+	// Set should the initiator only have verified the identity of the responder, in which
+	// case the session is not to be established.
+	probe bool
+}
+
+// ephemeralKeyPair is a single-use X25519 keypair used to derive the keys of a session.
+type ephemeralKeyPair struct {
+	private, public [sessionKeySize]byte
+}
+
+// newEphemeralKeyPair randomly generates a new X25519 keypair.
+func newEphemeralKeyPair() (*ephemeralKeyPair, error) {
+	pair := new(ephemeralKeyPair)
+	if _, err := io.ReadFull(rand.Reader, pair.private[:]); err != nil {
+		return nil, errors.Wrap(err, "session: failed to generate ephemeral key")
+	}
+	curve25519.ScalarBaseMult(&pair.public, &pair.private)
+
+	return pair, nil
+}
+
+// sessionSecrets holds the keys of a session, as seen from one end of the connection.
+type sessionSecrets struct {
+	// Keys encrypting data sent and received over the connection.
+	sendKey, recvKey []byte
+
+	// Keys authenticating messages sent and received over the connection.
+	sendMAC, recvMAC []byte
+}
+
+// deriveSessionSecrets derives the directional keys of a session given both peers' ephemeral
+// keys and the challenges exchanged during the handshake.
+func deriveSessionSecrets(pair *ephemeralKeyPair, remote []byte, initiator bool, localChallenge, remoteChallenge []byte) (*sessionSecrets, error) {
+	if len(remote) != sessionKeySize {
+		return nil, errInvalidEphemeralKey
+	}
+
+	var remotePublic, shared [sessionKeySize]byte
+	copy(remotePublic[:], remote)
+
+	curve25519.ScalarMult(&shared, &pair.private, &remotePublic)
+
+	// Reject low-order points which yield an all-zero shared secret.
+	var zero [sessionKeySize]byte
+	if shared == zero {
+		return nil, errInvalidEphemeralKey
+	}
+
+	// Bind the keys to the transcript of the handshake, ordered by role.
+	salt := append(append([]byte{}, localChallenge...), remoteChallenge...)
+	info := append(append([]byte(sessionKeyInfo), pair.public[:]...), remotePublic[:]...)
+	if !initiator {
+		salt = append(append([]byte{}, remoteChallenge...), localChallenge...)
+		info = append(append([]byte(sessionKeyInfo), remotePublic[:]...), pair.public[:]...)
+	}
+
+	// Keys are laid out as the initiator's and responder's encryption keys, followed
+	// by the initiator's and responder's MAC keys.
+	keys := make([]byte, 4*sessionKeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared[:], salt, info), keys); err != nil {
+		return nil, errors.Wrap(err, "session: failed to derive keys")
+	}
+
+	key := func(i int) []byte {
+		return keys[i*sessionKeySize : (i+1)*sessionKeySize]
+	}
+
+	if initiator {
+		return &sessionSecrets{sendKey: key(0), recvKey: key(1), sendMAC: key(2), recvMAC: key(3)}, nil
+	}
+	return &sessionSecrets{sendKey: key(1), recvKey: key(0), sendMAC: key(3), recvMAC: key(2)}, nil
+}
+
+// messageMAC computes the MAC of a message under a session key, covering the same bytes
+// a signature of the message does.
+func messageMAC(key []byte, msg *protobuf.Message) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(serializeAuthenticated(msg))
+
+	return mac.Sum(nil)
+}
+
+// sessionConn wraps a net.Conn such that all data written to it is split into length-prefixed
+// frames sealed with ChaCha20-Poly1305, keyed differently for each direction.
+type sessionConn struct {
+	net.Conn
+
+	writeMutex sync.Mutex
+	sealer     cipher.AEAD
+	sendNonce  uint64
+
+	readMutex sync.Mutex
+	opener    cipher.AEAD
+	recvNonce uint64
+	pending   []byte
+}
+
+// newSessionConn wraps conn with an encrypted session keyed by the given directional keys.
+func newSessionConn(conn net.Conn, send, recv []byte) (net.Conn, error) {
+	sealer, err := chacha20poly1305.New(send)
+	if err != nil {
+		return nil, errors.Wrap(err, "session: failed to create cipher")
+	}
+
+	opener, err := chacha20poly1305.New(recv)
+	if err != nil {
+		return nil, errors.Wrap(err, "session: failed to create cipher")
+	}
+
+	return &sessionConn{Conn: conn, sealer: sealer, opener: opener}, nil
+}
+
+func sessionNonce(counter uint64) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.BigEndian.PutUint64(nonce[chacha20poly1305.NonceSize-8:], counter)
+	return nonce
+}
+
+// Write seals b into one or more frames and writes them to the underlying connection.
+func (c *sessionConn) Write(b []byte) (int, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	written := 0
+
+	for len(b) > 0 {
+		chunk := b
+		if len(chunk) > maxSessionFrameSize {
+			chunk = chunk[:maxSessionFrameSize]
+		}
+
+		frame := make([]byte, 4, 4+len(chunk)+c.sealer.Overhead())
+		frame = c.sealer.Seal(frame, sessionNonce(c.sendNonce), chunk, nil)
+		c.sendNonce++
+
+		binary.BigEndian.PutUint32(frame[:4], uint32(len(frame)-4))
+
+		if _, err := c.Conn.Write(frame); err != nil {
+			return written, err
+		}
+
+		written += len(chunk)
+		b = b[len(chunk):]
+	}
+
+	return written, nil
+}
+
+// Read reads and opens frames from the underlying connection until b may be filled with
+// at least one byte of plaintext.
+func (c *sessionConn) Read(b []byte) (int, error) {
+	c.readMutex.Lock()
+	defer c.readMutex.Unlock()
+
+	for len(c.pending) == 0 {
+		var header [4]byte
+		if _, err := io.ReadFull(c.Conn, header[:]); err != nil {
+			return 0, err
+		}
+
+		size := binary.BigEndian.Uint32(header[:])
+		if size < uint32(c.opener.Overhead()) || size > uint32(maxSessionFrameSize+c.opener.Overhead()) {
+			return 0, errors.Errorf("session: received frame has invalid length %d", size)
+		}
+
+		frame := make([]byte, size)
+		if _, err := io.ReadFull(c.Conn, frame); err != nil {
+			return 0, err
+		}
+
+		plaintext, err := c.opener.Open(frame[:0], sessionNonce(c.recvNonce), frame, nil)
+		if err != nil {
+			return 0, errors.New("session: failed to authenticate received frame")
+		}
+		c.recvNonce++
+
+		c.pending = plaintext
+	}
+
+	n := copy(b, c.pending)
+	c.pending = c.pending[n:]
+
+	return n, nil
+}
@@ -0,0 +1,217 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/perlin-network/noise/protobuf"
+)
+
+func handshakePair(t *testing.T, initiator, responder *Network) (net.Conn, net.Conn, error, error) {
+	a, b := net.Pipe()
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		var conn net.Conn
+		session, err := responder.handshake(context.Background(), b, false, false, nil)
+		if err != nil {
+			b.Close()
+		} else {
+			conn = session.conn
+		}
+		done <- result{conn, err}
+	}()
+
+	var conn net.Conn
+	session, err := initiator.handshake(context.Background(), a, true, false, nil)
+	if err != nil {
+		a.Close()
+	} else {
+		conn = session.conn
+	}
+	res := <-done
+
+	return conn, res.conn, err, res.err
+}
+
+func TestEncryptedSession(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12011, EncryptSessions(true))
+	bob := buildHandshakeNetwork(t, 12012, EncryptSessions(true))
+
+	a, b, errA, errB := handshakePair(t, alice, bob)
+	if errA != nil || errB != nil {
+		t.Fatalf("handshake failed: %v, %v", errA, errB)
+	}
+	defer a.Close()
+	defer b.Close()
+
+	if _, ok := a.(*sessionConn); !ok {
+		t.Fatalf("expected an encrypted session, got %T", a)
+	}
+
+	// Span multiple frames in both directions.
+	payload := make([]byte, 3*maxSessionFrameSize+100)
+	rand.Read(payload)
+
+	for _, pair := range [][2]net.Conn{{a, b}, {b, a}} {
+		go func(w net.Conn) {
+			w.Write(payload)
+		}(pair[0])
+
+		received := make([]byte, len(payload))
+		if _, err := io.ReadFull(pair[1], received); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(received, payload) {
+			t.Fatal("received payload does not match the payload sent")
+		}
+	}
+}
+
+func TestEncryptedSessionMismatch(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12013, EncryptSessions(true))
+	bob := buildHandshakeNetwork(t, 12014)
+
+	_, _, errA, errB := handshakePair(t, alice, bob)
+	if errA == nil || errB == nil {
+		t.Fatalf("expected handshake between peers disagreeing on encryption to fail, got %v, %v", errA, errB)
+	}
+}
+
+func TestSessionTamperedFrame(t *testing.T) {
+	t.Parallel()
+
+	key := make([]byte, sessionKeySize)
+	rand.Read(key)
+
+	a, b := net.Pipe()
+	defer a.Close()
+	defer b.Close()
+
+	reader, err := newSessionConn(b, key, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Seal a frame, flip a bit of its ciphertext, then forward it.
+	go func() {
+		var wire bytes.Buffer
+		writer, _ := newSessionConn(&bufferConn{Conn: a, buffer: &wire}, key, key)
+		writer.Write([]byte("hello"))
+
+		frame := wire.Bytes()
+		frame[len(frame)-1] ^= 1
+		a.Write(frame)
+	}()
+
+	if _, err := reader.Read(make([]byte, 16)); err == nil {
+		t.Fatal("expected a tampered frame to be rejected")
+	}
+}
+
+// bufferConn reads from and captures everything written to it into a buffer.
+type bufferConn struct {
+	net.Conn
+	buffer *bytes.Buffer
+}
+
+func (c *bufferConn) Read(b []byte) (int, error) {
+	return c.buffer.Read(b)
+}
+
+func (c *bufferConn) Write(b []byte) (int, error) {
+	return c.buffer.Write(b)
+}
+
+func TestMessageMAC(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12015, MessageAuthentication(AuthSessionMAC))
+
+	msg, err := alice.PrepareMessage(&protobuf.Ping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Signature != nil {
+		t.Fatal("expected message to be left unsigned under session MACs")
+	}
+
+	key := make([]byte, sessionKeySize)
+	rand.Read(key)
+
+	msg.MessageNonce = 1
+	msg.Mac = messageMAC(key, msg)
+
+	var wire bytes.Buffer
+	if err := alice.sendMessage(&wire, msg, new(sync.Mutex), nil); err != nil {
+		t.Fatal(err)
+	}
+	frame := wire.Bytes()
+
+	if _, err := alice.receiveMessage(&bufferConn{buffer: bytes.NewBuffer(frame)}, key, nil); err != nil {
+		t.Fatalf("expected message with a valid MAC to be accepted: %+v", err)
+	}
+
+	if _, err := alice.receiveMessage(&bufferConn{buffer: bytes.NewBuffer(frame)}, nil, nil); err == nil {
+		t.Fatal("expected message with a MAC to be rejected outside of a session")
+	}
+
+	// Tamper with the nonce, which is covered by the MAC.
+	msg.MessageNonce = 2
+	wire.Reset()
+	alice.sendMessage(&wire, msg, new(sync.Mutex), nil)
+
+	if _, err := alice.receiveMessage(&bufferConn{buffer: &wire}, key, nil); err == nil {
+		t.Fatal("expected message with a tampered nonce to be rejected")
+	}
+}
+
+func TestMessageSignatureCoversNonces(t *testing.T) {
+	t.Parallel()
+
+	alice := buildHandshakeNetwork(t, 12051)
+
+	msg, err := alice.PrepareMessage(&protobuf.Ping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg.RequestNonce = 1
+	msg.MessageNonce = 1
+	if err := alice.sign(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	var wire bytes.Buffer
+	if err := alice.sendMessage(&wire, msg, new(sync.Mutex), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := alice.receiveMessage(&bufferConn{buffer: &wire}, nil, nil); err != nil {
+		t.Fatalf("expected message with a valid signature to be accepted: %+v", err)
+	}
+
+	// Replay the message under another nonce, which is covered by the signature.
+	msg.MessageNonce = 2
+	wire.Reset()
+	alice.sendMessage(&wire, msg, new(sync.Mutex), nil)
+
+	if _, err := alice.receiveMessage(&bufferConn{buffer: &wire}, nil, nil); err == nil {
+		t.Fatal("expected message replayed under another nonce to be rejected")
+	}
+}
@@ -0,0 +1,146 @@
+package network
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/glog"
+	"github.com/perlin-network/noise/protobuf"
+	"github.com/pkg/errors"
+)
+
+// ErrPeerShuttingDown is returned when sending new messages to a peer which announced
+// that it is shutting down.
+var ErrPeerShuttingDown = errors.New("network: peer is shutting down")
+
+// inflightTracker counts operations in flight, such that they may be waited upon to finish.
+type inflightTracker struct {
+	sync.Mutex
+
+	count int
+	idle  chan struct{}
+}
+
+// Add marks the start of an operation.
+func (t *inflightTracker) Add() {
+	t.Lock()
+	if t.count == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.count++
+	t.Unlock()
+}
+
+// Done marks the end of an operation.
+func (t *inflightTracker) Done() {
+	t.Lock()
+	t.count--
+	if t.count == 0 {
+		close(t.idle)
+	}
+	t.Unlock()
+}
+
+// Wait waits for all operations in flight to finish, giving up should ctx be done first.
+func (t *inflightTracker) Wait(ctx context.Context) error {
+	for {
+		t.Lock()
+		if t.count == 0 {
+			t.Unlock()
+			return nil
+		}
+		idle := t.idle
+		t.Unlock()
+
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Shutdown gracefully shuts down the node. It stops accepting new peers, tells connected
+// peers that it is going away, waits for plugin and RPC handlers and requests in flight to
+// finish, flushes all messages left to be written, and closes all connections, after which
+// plugins are cleaned up.
+//
+// Should ctx be done before handlers and requests finish, the node is closed regardless and
+// the error of ctx is returned.
+func (n *Network) Shutdown(ctx context.Context) error {
+	n.stopAccepting()
+
+	n.sayGoodbye()
+
+	err := n.inflight.Wait(ctx)
+
+	n.flushConnections()
+	n.Close()
+
+	return err
+}
+
+// Close immediately stops listening for peers and closes all connections, without waiting
+// for messages in flight. Use Shutdown to gracefully shut down the node instead.
+func (n *Network) Close() {
+	n.stopAccepting()
+
+	// Stop all I/O workers.
+	n.killOnce.Do(func() {
+		close(n.kill)
+	})
+
+	// Clean out client connections.
+	n.Peers.Range(func(key, value interface{}) bool {
+		value.(*PeerClient).Close()
+		return true
+	})
+
+	n.cleanup()
+}
+
+// stopAccepting stops accepting new peers.
+func (n *Network) stopAccepting() {
+	n.closingOnce.Do(func() {
+		close(n.closing)
+	})
+}
+
+// cleanup executes the 'network stops listening' callback for plugins, should the network
+// have started listening.
+func (n *Network) cleanup() {
+	if atomic.LoadUint32(&n.started) == 0 {
+		return
+	}
+
+	n.cleanupOnce.Do(func() {
+		n.Plugins.Each(func(plugin PluginInterface) {
+			plugin.Cleanup(n)
+		})
+	})
+}
+
+// sayGoodbye tells all connected peers that this node is shutting down.
+func (n *Network) sayGoodbye() {
+	msg, err := n.PrepareMessage(new(protobuf.Goodbye))
+	if err != nil {
+		glog.Warning(err)
+		return
+	}
+
+	n.Connections.Range(func(key, value interface{}) bool {
+		if err := n.write(value.(*ConnState), msg, PriorityControl, false); err != nil {
+			glog.Warningf("failed to say goodbye to %s [err=%s]", key, err)
+		}
+		return true
+	})
+
+	n.flushConnections()
+}
+
+// ShuttingDown returns true should the peer have announced that it is shutting down, after
+// which it may no longer be sent new messages.
+func (c *PeerClient) ShuttingDown() bool {
+	return atomic.LoadUint32(&c.shuttingDown) == 1
+}
@@ -0,0 +1,70 @@
+package network
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestInflightTracker(t *testing.T) {
+	t.Parallel()
+
+	var tracker inflightTracker
+
+	if err := tracker.Wait(context.Background()); err != nil {
+		t.Fatalf("expected nothing in flight to be waited upon, got %v", err)
+	}
+
+	tracker.Add()
+	tracker.Add()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := tracker.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected waiting on operations in flight to time out, got %v", err)
+	}
+
+	go func() {
+		tracker.Done()
+		tracker.Done()
+	}()
+
+	if err := tracker.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOrderedDeliveriesInflight(t *testing.T) {
+	t.Parallel()
+
+	n, err := NewBuilderWithOptions(OrderedDelivery(true)).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := createPeerClient(n, "tcp://127.0.0.1:12052")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go client.executeDeliveries()
+
+	release := make(chan struct{})
+	n.runHandler(client, func() { <-release })
+	n.runHandler(client, func() {})
+
+	// The second handler is yet to be delivered, though it is already in flight.
+	n.inflight.Lock()
+	count := n.inflight.count
+	n.inflight.Unlock()
+
+	if count != 2 {
+		t.Fatalf("expected handlers queued up to be in flight, got %d in flight", count)
+	}
+
+	close(release)
+
+	if err := n.inflight.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
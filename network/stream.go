@@ -2,6 +2,7 @@ package network
 
 import (
 	"bufio"
+	"crypto/hmac"
 	"encoding/binary"
 	"io"
 	"net"
@@ -16,16 +17,29 @@ import (
 
 var errEmptyMsg = errors.New("received an empty message from a peer")
 
-// sendMessage marshals, signs and sends a message over a stream.
-func (n *Network) sendMessage(w io.Writer, message *protobuf.Message, writerMutex *sync.Mutex) error {
+// sendMessage marshals, signs and sends a message over a stream. Should compression be
+// non-nil, the message is compressed with it.
+func (n *Network) sendMessage(w io.Writer, message *protobuf.Message, writerMutex *sync.Mutex, compression *frameCompression) error {
 	bytes, err := proto.Marshal(message)
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal message")
 	}
 
-	// Serialize size.
+	compressed := false
+	if compression != nil {
+		if bytes, compressed, err = compression.compress(bytes); err != nil {
+			return err
+		}
+	}
+
+	// Serialize size, flagging compressed messages.
+	header := uint32(len(bytes))
+	if compressed {
+		header |= frameCompressedFlag
+	}
+
 	buffer := make([]byte, 4)
-	binary.BigEndian.PutUint32(buffer, uint32(len(bytes)))
+	binary.BigEndian.PutUint32(buffer, header)
 
 	buffer = append(buffer, bytes...)
 	totalSize := len(buffer)
@@ -59,8 +73,11 @@ func (n *Network) sendMessage(w io.Writer, message *protobuf.Message, writerMute
 	return nil
 }
 
-// receiveMessage reads, unmarshals and verifies a message from a net.Conn.
-func (n *Network) receiveMessage(conn net.Conn) (*protobuf.Message, error) {
+// receiveMessage reads, unmarshals and verifies a message from a net.Conn. Messages
+// authenticated by a session MAC are verified against macKey, and are rejected should
+// macKey be nil. Compressed messages are decompressed with compression, and are rejected
+// should compression be nil.
+func (n *Network) receiveMessage(conn net.Conn, macKey []byte, compression *frameCompression) (*protobuf.Message, error) {
 	var err error
 
 	// Read until all header bytes have been read.
@@ -76,13 +93,15 @@ func (n *Network) receiveMessage(conn net.Conn) (*protobuf.Message, error) {
 	// Decode message size.
 	size := binary.BigEndian.Uint32(buffer)
 
+	compressed := size&frameCompressedFlag != 0
+	size &^= frameCompressedFlag
+
 	if size == 0 {
 		return nil, errEmptyMsg
 	}
 
-	// Message size at most is limited to 4MB. If a big message need be sent,
-	// consider partitioning to message into chunks of 4MB.
-	if size > 4e+6 {
+	// Message size at most is limited to 4MB. Bigger messages are sent in chunks.
+	if size > maxFrameSize {
 		return nil, errors.Errorf("message has length of %d which is either broken or too large", size)
 	}
 
@@ -96,6 +115,16 @@ func (n *Network) receiveMessage(conn net.Conn) (*protobuf.Message, error) {
 		totalBytesRead += bytesRead
 	}
 
+	if compressed && compression == nil {
+		return nil, errors.New("received a compressed message over a connection without compression")
+	}
+
+	if compression != nil {
+		if buffer, err = compression.decompress(buffer, compressed); err != nil {
+			return nil, err
+		}
+	}
+
 	// Deserialize message.
 	msg := new(protobuf.Message)
 
@@ -105,16 +134,29 @@ func (n *Network) receiveMessage(conn net.Conn) (*protobuf.Message, error) {
 	}
 
 	// Check if any of the message headers are invalid or null.
-	if msg.Message == nil || msg.Sender == nil || msg.Sender.PublicKey == nil || len(msg.Sender.Address) == 0 || msg.Signature == nil {
+	if msg.Message == nil || msg.Sender == nil || msg.Sender.PublicKey == nil || len(msg.Sender.Address) == 0 || (msg.Signature == nil && msg.Mac == nil) {
 		return nil, errors.New("received an invalid message (either no message, no sender, or no signature) from a peer")
 	}
 
+	// Verify session MAC of message.
+	if msg.Signature == nil {
+		if macKey == nil {
+			return nil, errors.New("received message authenticated by a session MAC outside of a session")
+		}
+
+		if !hmac.Equal(msg.Mac, messageMAC(macKey, msg)) {
+			return nil, errors.New("received message had an malformed MAC")
+		}
+
+		return msg, nil
+	}
+
 	// Verify signature of message.
 	if !crypto.Verify(
 		n.opts.signaturePolicy,
 		n.opts.hashPolicy,
 		msg.Sender.PublicKey,
-		SerializeMessage(msg.Sender, msg.Message.Value),
+		serializeAuthenticated(msg),
 		msg.Signature,
 	) {
 		return nil, errors.New("received message had an malformed signature")
@@ -0,0 +1,480 @@
+package transport
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// faultyQueueSize is the number of writes which may be awaiting delivery over a connection
+// before further writes block.
+const faultyQueueSize = 1024
+
+var (
+	errFaultyConnClosed = errors.New("transport: use of closed connection")
+	errFaultyConnReset  = errors.New("transport: connection reset by injected fault")
+)
+
+// FaultConfig configures the faults injected into connections.
+type FaultConfig struct {
+	// Latency delays the delivery of writes, by a further random duration of up to Jitter.
+	// Writes are delivered in order regardless of jitter.
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// Bandwidth caps the number of bytes per second delivered over each connection, or
+	// zero to leave it uncapped.
+	Bandwidth int
+
+	// DropRate is the probability of a write being silently dropped. Writes are dropped
+	// whole, so peers of stream transports see a corrupted stream.
+	DropRate float64
+
+	// ResetRate is the probability of a write resetting its connection instead.
+	ResetRate float64
+}
+
+// FaultInjector injects faults into the connections of transport layers wrapped by it,
+// configurable at runtime such that tests may simulate failures as they happen.
+//
+// A single injector is meant to be shared by all nodes of a test, each registering a
+// transport layer wrapped with its own address:
+//
+//	faults := transport.NewFaultInjector()
+//	builder.RegisterTransportLayer("tcp", faults.Wrap(transport.NewTCP(), "tcp://127.0.0.1:3000"))
+type FaultInjector struct {
+	mutex      sync.RWMutex
+	config     FaultConfig
+	partitions []faultPartition
+	conns      map[*faultyConn]struct{}
+
+	randMutex sync.Mutex
+	rand      *rand.Rand
+}
+
+// faultPartition separates two sets of addresses from each other.
+type faultPartition struct {
+	a, b map[string]struct{}
+}
+
+func (p faultPartition) separates(x, y string) bool {
+	_, xa := p.a[x]
+	_, xb := p.b[x]
+	_, ya := p.a[y]
+	_, yb := p.b[y]
+	return (xa && yb) || (xb && ya)
+}
+
+// NewFaultInjector instantiates a new fault injector which injects no faults until
+// configured to.
+func NewFaultInjector() *FaultInjector {
+	return &FaultInjector{
+		conns: make(map[*faultyConn]struct{}),
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Seed seeds the source of randomness deciding jitter, drops and resets, such that runs
+// of a test inject the same faults.
+func (i *FaultInjector) Seed(seed int64) {
+	i.randMutex.Lock()
+	i.rand.Seed(seed)
+	i.randMutex.Unlock()
+}
+
+// Configure sets the faults injected into all connections, including established ones.
+func (i *FaultInjector) Configure(config FaultConfig) {
+	i.mutex.Lock()
+	i.config = config
+	i.mutex.Unlock()
+}
+
+// Config returns the faults currently injected into connections.
+func (i *FaultInjector) Config() FaultConfig {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	return i.config
+}
+
+// Partition separates two sets of addresses from each other, resetting connections dialed
+// between them and failing further dials until healed. Addresses may be given with or
+// without a protocol, such as tcp://127.0.0.1:3000 or 127.0.0.1:3000.
+func (i *FaultInjector) Partition(a []string, b []string) {
+	partition := faultPartition{a: make(map[string]struct{}), b: make(map[string]struct{})}
+	for _, address := range a {
+		partition.a[normalizeFaultAddress(address)] = struct{}{}
+	}
+	for _, address := range b {
+		partition.b[normalizeFaultAddress(address)] = struct{}{}
+	}
+
+	i.mutex.Lock()
+	i.partitions = append(i.partitions, partition)
+
+	var separated []*faultyConn
+	for conn := range i.conns {
+		if partition.separates(conn.local, conn.remote) {
+			separated = append(separated, conn)
+		}
+	}
+	i.mutex.Unlock()
+
+	for _, conn := range separated {
+		conn.reset()
+	}
+}
+
+// Heal removes all partitions.
+func (i *FaultInjector) Heal() {
+	i.mutex.Lock()
+	i.partitions = nil
+	i.mutex.Unlock()
+}
+
+// ResetConnections resets all connections established through the injector.
+func (i *FaultInjector) ResetConnections() {
+	i.mutex.RLock()
+	conns := make([]*faultyConn, 0, len(i.conns))
+	for conn := range i.conns {
+		conns = append(conns, conn)
+	}
+	i.mutex.RUnlock()
+
+	for _, conn := range conns {
+		conn.reset()
+	}
+}
+
+// Wrap wraps a transport layer of the node residing at address, injecting faults into the
+// connections it dials and accepts. See Partition for the forms address may take.
+func (i *FaultInjector) Wrap(layer Layer, address string) *Faulty {
+	return &Faulty{
+		Layer:    layer,
+		address:  normalizeFaultAddress(address),
+		injector: i,
+	}
+}
+
+func (i *FaultInjector) partitioned(x, y string) bool {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	for _, partition := range i.partitions {
+		if partition.separates(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *FaultInjector) chance(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+
+	i.randMutex.Lock()
+	defer i.randMutex.Unlock()
+
+	return i.rand.Float64() < probability
+}
+
+func (i *FaultInjector) jitter(jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+
+	i.randMutex.Lock()
+	defer i.randMutex.Unlock()
+
+	return time.Duration(i.rand.Int63n(int64(jitter) + 1))
+}
+
+func (i *FaultInjector) track(conn *faultyConn) {
+	i.mutex.Lock()
+	i.conns[conn] = struct{}{}
+	i.mutex.Unlock()
+}
+
+func (i *FaultInjector) untrack(conn *faultyConn) {
+	i.mutex.Lock()
+	delete(i.conns, conn)
+	i.mutex.Unlock()
+}
+
+// normalizeFaultAddress strips the protocol off an address and writes its host in its
+// canonical form should it be an IP address.
+func normalizeFaultAddress(address string) string {
+	var path string
+
+	if strings.Contains(address, "://") {
+		if info, err := url.Parse(address); err == nil {
+			address, path = info.Host, info.Path
+		}
+	} else if i := strings.Index(address, "/"); i > 0 {
+		address, path = address[:i], address[i:]
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address + path
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		host = ip.String()
+	}
+
+	return net.JoinHostPort(host, port) + path
+}
+
+// Faulty represents a transport layer wrapped by a FaultInjector.
+//
+// Partitions apply to connections dialed through Faulty layers, as only the addresses
+// peers are dialed at are known. Connections accepted are subject to all other faults.
+type Faulty struct {
+	// Layer dials and listens for the connections faults are injected into.
+	Layer Layer
+
+	address  string
+	injector *FaultInjector
+}
+
+// Listen listens for incoming connections through the wrapped layer.
+func (t *Faulty) Listen(address string) (net.Listener, error) {
+	listener, err := t.Layer.Listen(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &faultyListener{Listener: listener, transport: t}, nil
+}
+
+// Dial dials an address through the wrapped layer.
+func (t *Faulty) Dial(address string) (net.Conn, error) {
+	return t.DialContext(context.Background(), address)
+}
+
+// DialContext dials an address through the wrapped layer, failing should the address be
+// partitioned from the address of the node.
+func (t *Faulty) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	remote := normalizeFaultAddress(address)
+	if t.injector.partitioned(t.address, remote) {
+		return nil, errors.Errorf("transport: %s is partitioned from %s", remote, t.address)
+	}
+
+	conn, err := t.Layer.DialContext(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFaultyConn(conn, t.injector, t.address, remote), nil
+}
+
+// faultyListener injects faults into the connections accepted by a listener.
+type faultyListener struct {
+	net.Listener
+	transport *Faulty
+}
+
+// Accept implements net.Listener.
+func (l *faultyListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return newFaultyConn(conn, l.transport.injector, l.transport.address, ""), nil
+}
+
+// faultyWrite is a write awaiting delivery over a connection.
+type faultyWrite struct {
+	data    []byte
+	deliver time.Time
+}
+
+// faultyConn injects faults into writes to a connection, delivering them in order from a
+// queue such that latency does not hold up writers.
+type faultyConn struct {
+	net.Conn
+
+	injector      *FaultInjector
+	local, remote string
+
+	queue       chan faultyWrite
+	lastDeliver time.Time
+	writeMutex  sync.Mutex
+
+	// closing is closed once the connection is closed, after which writes awaiting delivery
+	// are still delivered. done is closed once the wrapped connection is closed.
+	closing     chan struct{}
+	closingOnce sync.Once
+	done        chan struct{}
+	doneOnce    sync.Once
+}
+
+func newFaultyConn(conn net.Conn, injector *FaultInjector, local string, remote string) *faultyConn {
+	c := &faultyConn{
+		Conn:     conn,
+		injector: injector,
+		local:    local,
+		remote:   remote,
+		queue:    make(chan faultyWrite, faultyQueueSize),
+		closing:  make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	injector.track(c)
+	go c.deliver()
+
+	return c
+}
+
+// Read implements net.Conn.
+func (c *faultyConn) Read(p []byte) (int, error) {
+	select {
+	case <-c.closing:
+		return 0, errFaultyConnClosed
+	default:
+	}
+
+	return c.Conn.Read(p)
+}
+
+// Write implements net.Conn, queueing p for delivery unless it is dropped or resets the
+// connection.
+func (c *faultyConn) Write(p []byte) (int, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	select {
+	case <-c.closing:
+		return 0, errFaultyConnClosed
+	case <-c.done:
+		return 0, errFaultyConnReset
+	default:
+	}
+
+	config := c.injector.Config()
+
+	if c.injector.partitioned(c.local, c.remote) || c.injector.chance(config.ResetRate) {
+		c.reset()
+		return 0, errFaultyConnReset
+	}
+
+	if c.injector.chance(config.DropRate) {
+		return len(p), nil
+	}
+
+	// Never deliver writes ahead of those written before them.
+	deliver := time.Now().Add(config.Latency + c.injector.jitter(config.Jitter))
+	if deliver.Before(c.lastDeliver) {
+		deliver = c.lastDeliver
+	}
+	c.lastDeliver = deliver
+
+	write := faultyWrite{data: append([]byte(nil), p...), deliver: deliver}
+
+	select {
+	case c.queue <- write:
+		return len(p), nil
+	case <-c.closing:
+		return 0, errFaultyConnClosed
+	case <-c.done:
+		return 0, errFaultyConnReset
+	}
+}
+
+// deliver writes queued writes to the wrapped connection once they are due, closing the
+// wrapped connection once all writes queued before the connection was closed are delivered.
+func (c *faultyConn) deliver() {
+	defer c.finish()
+
+	for {
+		select {
+		case write := <-c.queue:
+			if !c.write(write) {
+				return
+			}
+		case <-c.closing:
+			for {
+				select {
+				case write := <-c.queue:
+					if !c.write(write) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// write delivers a write once it is due, returning false should the wrapped connection
+// be closed meanwhile or fail.
+func (c *faultyConn) write(write faultyWrite) bool {
+	if !c.wait(time.Until(write.deliver)) {
+		return false
+	}
+
+	if bandwidth := c.injector.Config().Bandwidth; bandwidth > 0 {
+		if !c.wait(time.Duration(len(write.data)) * time.Second / time.Duration(bandwidth)) {
+			return false
+		}
+	}
+
+	_, err := c.Conn.Write(write.data)
+	return err == nil
+}
+
+// wait waits for a duration, returning false should the wrapped connection be closed
+// meanwhile.
+func (c *faultyConn) wait(duration time.Duration) bool {
+	if duration <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+// Close implements net.Conn. Writes awaiting delivery are delivered before the wrapped
+// connection is closed, as they would be by a socket.
+func (c *faultyConn) Close() error {
+	c.closingOnce.Do(func() {
+		close(c.closing)
+	})
+	return nil
+}
+
+// reset closes the wrapped connection abruptly, discarding writes awaiting delivery.
+func (c *faultyConn) reset() {
+	if tcpConn, ok := c.Conn.(*net.TCPConn); ok {
+		tcpConn.SetLinger(0)
+	}
+	c.finish()
+}
+
+// finish closes the wrapped connection.
+func (c *faultyConn) finish() {
+	c.doneOnce.Do(func() {
+		close(c.done)
+		c.injector.untrack(c)
+		c.Conn.Close()
+	})
+}
@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// dialFaulty dials a listener through a layer wrapped by faults, returning both ends of the
+// connection dialed.
+func dialFaulty(t *testing.T, layer *Faulty, listener net.Listener) (net.Conn, net.Conn) {
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			accepted <- nil
+			return
+		}
+		accepted <- conn
+	}()
+
+	client, err := layer.Dial(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := <-accepted
+	if server == nil {
+		t.Fatal("expected listener to accept the connection dialed")
+	}
+
+	return client, server
+}
+
+func TestFaultyLatency(t *testing.T) {
+	t.Parallel()
+
+	faults := NewFaultInjector()
+	faults.Configure(FaultConfig{Latency: 50 * time.Millisecond})
+
+	listener, err := faults.Wrap(NewMemory(), "127.0.0.1:0").Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	client, server := dialFaulty(t, faults.Wrap(NewMemory(), "127.0.0.1:1"), listener)
+	defer client.Close()
+	defer server.Close()
+
+	start := time.Now()
+	for _, word := range []string{"one", "two"} {
+		if _, err := client.Write([]byte(word)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := make([]byte, 6)
+	if _, err := io.ReadFull(server, buf); err != nil || string(buf) != "onetwo" {
+		t.Fatalf("expected writes to be delivered in order, got %q (%v)", buf, err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected writes to be delayed by latency, got delivered after %s", elapsed)
+	}
+
+	// Writes queued before closing are still delivered.
+	client.Write([]byte("bye"))
+	client.Close()
+
+	rest := make([]byte, 3)
+	if _, err := io.ReadFull(server, rest); err != nil || string(rest) != "bye" {
+		t.Errorf("expected writes queued before closing to be delivered, got %q (%v)", rest, err)
+	}
+}
+
+func TestFaultyPartition(t *testing.T) {
+	t.Parallel()
+
+	faults := NewFaultInjector()
+
+	listener, err := NewMemory().Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	alice := faults.Wrap(NewMemory(), "memory://127.0.0.1:1")
+	remote := listener.Addr().String()
+
+	client, server := dialFaulty(t, alice, listener)
+	defer server.Close()
+
+	faults.Partition([]string{"127.0.0.1:1"}, []string{remote})
+
+	if _, err := server.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("expected connection across the partition to be reset, got %v", err)
+	}
+	if _, err := client.Write([]byte("ping")); err == nil {
+		t.Error("expected writing to a connection across the partition to fail")
+	}
+	if _, err := alice.Dial(remote); err == nil {
+		t.Error("expected dialing across the partition to fail")
+	}
+
+	faults.Heal()
+
+	client, server = dialFaulty(t, alice, listener)
+	defer server.Close()
+
+	faults.Configure(FaultConfig{DropRate: 1})
+	client.Write([]byte("dropped"))
+
+	faults.ResetConnections()
+
+	if _, err := server.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("expected dropped writes not to be delivered before the connection was reset, got %v", err)
+	}
+}
@@ -1,50 +1,188 @@
 package transport
 
 import (
-	"github.com/xtaci/kcp-go"
+	"context"
 	"net"
-	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xtaci/kcp-go"
 )
 
-// KCP represents the KCP transport protocol alongside its respective configurable options.
+const (
+	// Bounds of the options of KCP sessions, outside of which kcp-go ignores them.
+	kcpMinMTU      = 50
+	kcpMaxMTU      = 1500
+	kcpMinInterval = 10 * time.Millisecond
+	kcpMaxInterval = 5 * time.Second
+
+	// kcpMaxShards is the maximum number of Reed-Solomon shards a message may be split into.
+	kcpMaxShards = 256
+)
+
+// KCP represents the KCP transport protocol alongside its respective configurable options,
+// which apply to sessions both dialed and accepted.
 type KCP struct {
-	DataShards     int
-	ParityShards   int
+	// Numbers of Reed-Solomon data and parity shards messages are split into for forward
+	// error correction, or zero for both to disable it. Peers must use the same numbers.
+	DataShards   int
+	ParityShards int
+
 	SendWindowSize int
 	RecvWindowSize int
+
+	// NoDelay, Interval, Resend and NoCongestion tune how eagerly segments are sent and
+	// retransmitted, being the options of kcp-go's SetNoDelay.
+	NoDelay      bool
+	Interval     time.Duration
+	Resend       int
+	NoCongestion bool
+
+	// MTU is the maximum size of datagrams sent.
+	MTU int
+
+	// StreamMode merges messages into segments as a stream rather than sending each message
+	// in segments of its own.
+	StreamMode bool
 }
 
-// NewKCP instantiates a new instance of the KCP transport protocol.
+// NewKCP instantiates a new instance of the KCP transport protocol. Segments are sent and
+// resent eagerly without congestion control by default, as messages of a few megabytes
+// otherwise take close to a minute to be delivered.
 func NewKCP() *KCP {
 	return &KCP{
 		DataShards:     0,
 		ParityShards:   0,
 		SendWindowSize: 10000,
 		RecvWindowSize: 10000,
+		NoDelay:        true,
+		Interval:       20 * time.Millisecond,
+		Resend:         2,
+		NoCongestion:   true,
+		MTU:            1400,
+	}
+}
+
+// Validate returns an error should any option be out of range.
+func (t *KCP) Validate() error {
+	if t.DataShards < 0 || t.ParityShards < 0 || (t.DataShards == 0) != (t.ParityShards == 0) {
+		return errors.Errorf("transport: kcp data and parity shards must either both be positive or both be zero, got %d and %d", t.DataShards, t.ParityShards)
 	}
+
+	if t.DataShards+t.ParityShards > kcpMaxShards {
+		return errors.Errorf("transport: kcp messages may be split into at most %d shards, got %d", kcpMaxShards, t.DataShards+t.ParityShards)
+	}
+
+	if t.SendWindowSize <= 0 || t.RecvWindowSize <= 0 {
+		return errors.Errorf("transport: kcp window sizes must be positive, got %d and %d", t.SendWindowSize, t.RecvWindowSize)
+	}
+
+	if t.Interval < kcpMinInterval || t.Interval > kcpMaxInterval {
+		return errors.Errorf("transport: kcp interval must be between %s and %s, got %s", kcpMinInterval, kcpMaxInterval, t.Interval)
+	}
+
+	if t.Resend < 0 {
+		return errors.Errorf("transport: kcp resend must not be negative, got %d", t.Resend)
+	}
+
+	if t.MTU < kcpMinMTU || t.MTU > kcpMaxMTU {
+		return errors.Errorf("transport: kcp mtu must be between %d and %d, got %d", kcpMinMTU, kcpMaxMTU, t.MTU)
+	}
+
+	return nil
 }
 
-// Listen listens for incoming KCP connections on a specified port, with optional Reed-Solomon message sharding.
-func (t *KCP) Listen(port int) (net.Listener, error) {
-	listener, err := kcp.ListenWithOptions(":"+strconv.Itoa(port), nil, t.DataShards, t.ParityShards)
+// configure applies the options to a session.
+func (t *KCP) configure(session *kcp.UDPSession) {
+	session.SetWindowSize(t.SendWindowSize, t.RecvWindowSize)
+	session.SetNoDelay(boolToInt(t.NoDelay), int(t.Interval/time.Millisecond), t.Resend, boolToInt(t.NoCongestion))
+	session.SetMtu(t.MTU)
+	session.SetStreamMode(t.StreamMode)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Listen listens for incoming KCP connections on an address of the form host:port, or on all
+// interfaces should host be empty, with optional Reed-Solomon message sharding.
+func (t *KCP) Listen(address string) (net.Listener, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
+	listener, err := kcp.ListenWithOptions(address, nil, t.DataShards, t.ParityShards)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return listener, nil
+	return &kcpListener{Listener: listener, transport: t}, nil
+}
+
+// kcpListener applies the options of the KCP transport protocol to sessions accepted.
+type kcpListener struct {
+	*kcp.Listener
+	transport *KCP
+}
+
+// Accept accepts the next incoming KCP session.
+func (l *kcpListener) Accept() (net.Conn, error) {
+	conn, err := l.AcceptKCP()
+	if err != nil {
+		return nil, err
+	}
+
+	l.transport.configure(conn)
+
+	return conn, nil
 }
 
 // Dial dials an address via. the KCP protocol, with optional Reed-Solomon message sharding and
-// send/receive window size configurations.
+// the session configured by the options.
 func (t *KCP) Dial(address string) (net.Conn, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+
 	conn, err := kcp.DialWithOptions(address, nil, t.DataShards, t.ParityShards)
 
 	if err != nil {
 		return nil, err
 	}
 
-	conn.SetWindowSize(t.SendWindowSize, t.RecvWindowSize)
+	t.configure(conn)
 
 	return conn, nil
 }
+
+// DialContext dials an address via. the KCP protocol, giving up should ctx be done before the
+// session is established.
+func (t *KCP) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+
+	// KCP dials may not be interrupted, so abandon the dial and close its session once done.
+	dialed := make(chan result, 1)
+	go func() {
+		conn, err := t.Dial(address)
+		dialed <- result{conn, err}
+	}()
+
+	select {
+	case res := <-dialed:
+		return res.conn, res.err
+	case <-ctx.Done():
+		go func() {
+			if res := <-dialed; res.conn != nil {
+				res.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,34 @@
+package transport
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKCPValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		description string
+		configure   func(*KCP)
+		valid       bool
+	}{
+		{"defaults", func(*KCP) {}, true},
+		{"forward error correction", func(k *KCP) { k.DataShards, k.ParityShards = 10, 3 }, true},
+		{"data shards without parity shards", func(k *KCP) { k.DataShards = 10 }, false},
+		{"negative shards", func(k *KCP) { k.DataShards, k.ParityShards = -1, 3 }, false},
+		{"too many shards", func(k *KCP) { k.DataShards, k.ParityShards = 200, 100 }, false},
+		{"empty window", func(k *KCP) { k.RecvWindowSize = 0 }, false},
+		{"interval too short", func(k *KCP) { k.Interval = time.Millisecond }, false},
+		{"negative resend", func(k *KCP) { k.Resend = -1 }, false},
+		{"mtu too large", func(k *KCP) { k.MTU = 9000 }, false},
+	}
+	for _, tt := range testCases {
+		layer := NewKCP()
+		tt.configure(layer)
+
+		if err := layer.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate() = %v with %s, expected valid to be %t", err, tt.description, tt.valid)
+		}
+	}
+}
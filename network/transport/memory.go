@@ -0,0 +1,413 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// memoryNetwork is the name of the network memory addresses belong to.
+	memoryNetwork = "memory"
+
+	// firstEphemeralPort is the first port assigned to listeners bound to port 0 and to
+	// connections dialed.
+	firstEphemeralPort = 49152
+)
+
+// memoryListeners is the registry of listeners shared by all instances of the memory
+// transport protocol within a process, keyed by the addresses they listen on.
+var memoryListeners = &memoryRegistry{
+	listeners: make(map[string]*memoryListener),
+	nextPort:  firstEphemeralPort,
+}
+
+// Memory represents a transport protocol connecting peers within a single process without
+// any sockets, so that whole clusters may be run deterministically in tests and simulations.
+//
+// Listeners are registered in a registry shared by all instances of the protocol, so that
+// networks built with separate instances may dial each other.
+type Memory struct{}
+
+// NewMemory instantiates a new instance of the memory transport protocol.
+func NewMemory() *Memory {
+	return new(Memory)
+}
+
+// Listen listens for incoming in-memory connections on an address of the form host:port, or
+// on all hosts should host be empty. Listening on port 0 assigns a free port.
+func (t *Memory) Listen(address string) (net.Listener, error) {
+	return memoryListeners.listen(address)
+}
+
+// Dial dials an address in-memory.
+func (t *Memory) Dial(address string) (net.Conn, error) {
+	return t.DialContext(context.Background(), address)
+}
+
+// DialContext dials an address in-memory, aborting should ctx be done before the
+// connection is accepted.
+func (t *Memory) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	return memoryListeners.dial(ctx, address)
+}
+
+// memoryRegistry keeps track of the listeners of the memory transport protocol.
+type memoryRegistry struct {
+	sync.Mutex
+
+	listeners map[string]*memoryListener
+	nextPort  int
+}
+
+func (r *memoryRegistry) listen(address string) (*memoryListener, error) {
+	host, port, err := splitMemoryAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if port == "0" {
+		port = r.ephemeralPort(host)
+	}
+
+	key := net.JoinHostPort(host, port)
+	if _, exists := r.listeners[key]; exists {
+		return nil, errors.Errorf("transport: memory address %s already in use", key)
+	}
+
+	listener := &memoryListener{
+		registry: r,
+		key:      key,
+		addr:     memoryAddr(key),
+		conns:    make(chan net.Conn),
+		done:     make(chan struct{}),
+	}
+	r.listeners[key] = listener
+
+	return listener, nil
+}
+
+func (r *memoryRegistry) dial(ctx context.Context, address string) (net.Conn, error) {
+	host, port, err := splitMemoryAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Lock()
+
+	// Prefer listeners bound to the host dialed over those listening on all hosts.
+	listener, exists := r.listeners[net.JoinHostPort(host, port)]
+	if !exists {
+		listener, exists = r.listeners[net.JoinHostPort("", port)]
+	}
+	local := memoryAddr(net.JoinHostPort(host, r.ephemeralPort(host)))
+
+	r.Unlock()
+
+	if !exists {
+		return nil, errors.Errorf("transport: no memory listener at %s", address)
+	}
+
+	conn, peer := newMemoryPipe(local, listener.addr)
+
+	select {
+	case listener.conns <- peer:
+		return conn, nil
+	case <-listener.done:
+		conn.Close()
+		return nil, errors.Errorf("transport: memory listener at %s closed", address)
+	case <-ctx.Done():
+		conn.Close()
+		return nil, ctx.Err()
+	}
+}
+
+// ephemeralPort returns the next port not listened on by host. The registry must be locked.
+func (r *memoryRegistry) ephemeralPort(host string) string {
+	for {
+		port := strconv.Itoa(r.nextPort)
+
+		r.nextPort++
+		if r.nextPort > 65535 {
+			r.nextPort = firstEphemeralPort
+		}
+
+		if _, exists := r.listeners[net.JoinHostPort(host, port)]; !exists {
+			return port
+		}
+	}
+}
+
+// splitMemoryAddress splits an address of the form host:port, treating hosts which are
+// unspecified IP addresses as all hosts and writing IP addresses in their canonical form.
+func splitMemoryAddress(address string) (string, string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", "", err
+	}
+
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", "", errors.Errorf("transport: invalid memory port %q", port)
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		host = ip.String()
+		if ip.IsUnspecified() {
+			host = ""
+		}
+	}
+
+	return host, port, nil
+}
+
+// memoryAddr represents the address of an end of an in-memory connection.
+type memoryAddr string
+
+// Network implements net.Addr.
+func (a memoryAddr) Network() string {
+	return memoryNetwork
+}
+
+// String implements net.Addr.
+func (a memoryAddr) String() string {
+	return string(a)
+}
+
+// memoryListener accepts in-memory connections dialed to its address.
+type memoryListener struct {
+	registry *memoryRegistry
+	key      string
+	addr     memoryAddr
+
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Accept implements net.Listener.
+func (l *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, errors.Errorf("transport: memory listener at %s closed", l.addr)
+	}
+}
+
+// Close implements net.Listener, releasing the address of the listener.
+func (l *memoryListener) Close() error {
+	l.closeOnce.Do(func() {
+		l.registry.Lock()
+		delete(l.registry.listeners, l.key)
+		l.registry.Unlock()
+
+		close(l.done)
+	})
+	return nil
+}
+
+// Addr implements net.Listener.
+func (l *memoryListener) Addr() net.Addr {
+	return l.addr
+}
+
+// memoryTimeoutError is returned by reads and writes of in-memory connections whose
+// deadlines have passed.
+type memoryTimeoutError struct{}
+
+func (memoryTimeoutError) Error() string   { return "transport: memory connection i/o timeout" }
+func (memoryTimeoutError) Timeout() bool   { return true }
+func (memoryTimeoutError) Temporary() bool { return true }
+
+// memoryBuffer buffers the bytes sent one way over an in-memory connection. Writes never
+// block, such that both ends of a connection may write to each other at once as they may
+// over sockets.
+type memoryBuffer struct {
+	sync.Mutex
+
+	data     bytes.Buffer
+	closed   bool
+	deadline time.Time
+
+	// changed is closed and replaced whenever the buffer changes, waking up blocked reads.
+	changed chan struct{}
+}
+
+func newMemoryBuffer() *memoryBuffer {
+	return &memoryBuffer{changed: make(chan struct{})}
+}
+
+// notify wakes up reads blocked on the buffer. The buffer must be locked.
+func (b *memoryBuffer) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+func (b *memoryBuffer) read(p []byte, done chan struct{}) (int, error) {
+	for {
+		select {
+		case <-done:
+			return 0, io.ErrClosedPipe
+		default:
+		}
+
+		b.Lock()
+
+		if b.data.Len() > 0 {
+			n, err := b.data.Read(p)
+			b.Unlock()
+			return n, err
+		}
+
+		if b.closed {
+			b.Unlock()
+			return 0, io.EOF
+		}
+
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if !b.deadline.IsZero() {
+			wait := time.Until(b.deadline)
+			if wait <= 0 {
+				b.Unlock()
+				return 0, memoryTimeoutError{}
+			}
+
+			timer = time.NewTimer(wait)
+			timeout = timer.C
+		}
+
+		changed := b.changed
+		b.Unlock()
+
+		select {
+		case <-changed:
+		case <-timeout:
+		case <-done:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+func (b *memoryBuffer) write(p []byte, deadline time.Time) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return 0, memoryTimeoutError{}
+	}
+
+	b.data.Write(p)
+	b.notify()
+
+	return len(p), nil
+}
+
+func (b *memoryBuffer) setDeadline(deadline time.Time) {
+	b.Lock()
+	b.deadline = deadline
+	b.notify()
+	b.Unlock()
+}
+
+func (b *memoryBuffer) close() {
+	b.Lock()
+	if !b.closed {
+		b.closed = true
+		b.notify()
+	}
+	b.Unlock()
+}
+
+// memoryConn represents an end of an in-memory connection.
+type memoryConn struct {
+	in, out       *memoryBuffer
+	local, remote memoryAddr
+
+	writeMutex    sync.Mutex
+	writeDeadline time.Time
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newMemoryPipe creates both ends of an in-memory connection between two addresses.
+func newMemoryPipe(local, remote memoryAddr) (*memoryConn, *memoryConn) {
+	a, b := newMemoryBuffer(), newMemoryBuffer()
+
+	return &memoryConn{in: a, out: b, local: local, remote: remote, done: make(chan struct{})},
+		&memoryConn{in: b, out: a, local: remote, remote: local, done: make(chan struct{})}
+}
+
+// Read implements net.Conn. Reads block until data is written by the other end, returning
+// io.EOF once the other end is closed and all data written has been read.
+func (c *memoryConn) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return c.in.read(p, c.done)
+}
+
+// Write implements net.Conn.
+func (c *memoryConn) Write(p []byte) (int, error) {
+	c.writeMutex.Lock()
+	deadline := c.writeDeadline
+	c.writeMutex.Unlock()
+
+	return c.out.write(p, deadline)
+}
+
+// Close implements net.Conn, closing both ends of the connection.
+func (c *memoryConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.in.close()
+		c.out.close()
+	})
+	return nil
+}
+
+// LocalAddr implements net.Conn.
+func (c *memoryConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+// RemoteAddr implements net.Conn.
+func (c *memoryConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// SetDeadline implements net.Conn.
+func (c *memoryConn) SetDeadline(t time.Time) error {
+	c.SetReadDeadline(t)
+	return c.SetWriteDeadline(t)
+}
+
+// SetReadDeadline implements net.Conn.
+func (c *memoryConn) SetReadDeadline(t time.Time) error {
+	c.in.setDeadline(t)
+	return nil
+}
+
+// SetWriteDeadline implements net.Conn.
+func (c *memoryConn) SetWriteDeadline(t time.Time) error {
+	c.writeMutex.Lock()
+	c.writeDeadline = t
+	c.writeMutex.Unlock()
+	return nil
+}
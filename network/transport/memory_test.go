@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestMemory(t *testing.T) {
+	t.Parallel()
+
+	layer := NewMemory()
+
+	listener, err := layer.Listen(":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	if _, err := layer.Listen(listener.Addr().String()); err == nil {
+		t.Error("expected listening on an address in use to fail")
+	}
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			accepted <- nil
+			return
+		}
+		accepted <- conn
+	}()
+
+	// Networks built with separate instances dial each other through the same registry.
+	client, err := NewMemory().Dial(net.JoinHostPort("127.0.0.1", port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	server := <-accepted
+	if server == nil {
+		t.Fatal("expected listener to accept the connection dialed")
+	}
+
+	if server.RemoteAddr().String() != client.LocalAddr().String() {
+		t.Errorf("expected ends of the connection to agree on addresses, got %s and %s", server.RemoteAddr(), client.LocalAddr())
+	}
+
+	// Both ends may write before reading anything.
+	if _, err := client.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.Write([]byte("pong")); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(server, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("expected server to read ping, got %q (%v)", buf, err)
+	}
+	if _, err := io.ReadFull(client, buf); err != nil || string(buf) != "pong" {
+		t.Errorf("expected client to read pong, got %q (%v)", buf, err)
+	}
+
+	server.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	if _, err := server.Read(buf); err == nil || !err.(net.Error).Timeout() {
+		t.Errorf("expected read past its deadline to time out, got %v", err)
+	}
+	server.SetReadDeadline(time.Time{})
+
+	client.Write([]byte("bye"))
+	client.Close()
+
+	if rest, err := ioutil.ReadAll(server); err != nil || string(rest) != "bye" {
+		t.Errorf("expected data written before closing to be read until EOF, got %q (%v)", rest, err)
+	}
+	if _, err := server.Write([]byte("ping")); err == nil {
+		t.Error("expected writing to a closed connection to fail")
+	}
+
+	listener.Close()
+
+	if _, err := layer.Dial(listener.Addr().String()); err == nil {
+		t.Error("expected dialing a closed listener to fail")
+	}
+}
@@ -1,47 +1,121 @@
 package transport
 
 import (
+	"context"
 	"net"
-	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
+// defaultTCPKeepAlive is the default period between keep-alive probes of idle connections.
+const defaultTCPKeepAlive = 3 * time.Minute
+
+// TCP represents the TCP transport protocol alongside its respective configurable options,
+// which apply to connections both dialed and accepted.
 type TCP struct {
+	// Sizes of the socket buffers of connections, left up to the operating system if zero,
+	// as they are by default.
 	WriteBufferSize int
 	ReadBufferSize  int
 	NoDelay         bool
+
+	// KeepAlive is the period between keep-alive probes of idle connections, or zero to
+	// disable keep-alives.
+	KeepAlive time.Duration
 }
 
+// NewTCP instantiates a new instance of the TCP transport protocol.
 func NewTCP() *TCP {
 	return &TCP{
-		WriteBufferSize: 10000,
-		ReadBufferSize:  10000,
-		NoDelay:         false,
+		NoDelay:   false,
+		KeepAlive: defaultTCPKeepAlive,
+	}
+}
+
+// Validate returns an error should any option be out of range.
+func (t *TCP) Validate() error {
+	if t.WriteBufferSize < 0 || t.ReadBufferSize < 0 {
+		return errors.Errorf("transport: tcp socket buffer sizes must not be negative, got %d and %d", t.WriteBufferSize, t.ReadBufferSize)
+	}
+
+	if t.KeepAlive < 0 {
+		return errors.Errorf("transport: tcp keep-alive period must not be negative, got %s", t.KeepAlive)
+	}
+
+	return nil
+}
+
+// configure applies the options to a connection.
+func (t *TCP) configure(conn *net.TCPConn) {
+	if t.WriteBufferSize > 0 {
+		conn.SetWriteBuffer(t.WriteBufferSize)
+	}
+	if t.ReadBufferSize > 0 {
+		conn.SetReadBuffer(t.ReadBufferSize)
+	}
+	conn.SetNoDelay(t.NoDelay)
+
+	if t.KeepAlive > 0 {
+		conn.SetKeepAlive(true)
+		conn.SetKeepAlivePeriod(t.KeepAlive)
+	} else {
+		conn.SetKeepAlive(false)
+	}
+}
+
+// Listen listens for incoming TCP connections on an address of the form host:port, or on
+// all interfaces over both IPv4 and IPv6 should host be empty.
+func (t *TCP) Listen(address string) (net.Listener, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
 	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tcpListener{TCPListener: listener.(*net.TCPListener), transport: t}, nil
 }
 
-func (t *TCP) Listen(port int) (net.Listener, error) {
-	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+// tcpListener applies the options of the TCP transport protocol to connections accepted.
+type tcpListener struct {
+	*net.TCPListener
+	transport *TCP
+}
+
+// Accept accepts the next incoming TCP connection.
+func (l *tcpListener) Accept() (net.Conn, error) {
+	conn, err := l.AcceptTCP()
 	if err != nil {
 		return nil, err
 	}
 
-	return listener, nil
+	l.transport.configure(conn)
+
+	return conn, nil
 }
 
 func (t *TCP) Dial(address string) (net.Conn, error) {
-	resolved, err := net.ResolveTCPAddr("tcp", address)
-	if err != nil {
+	return t.DialContext(context.Background(), address)
+}
+
+// DialContext dials an address via. the TCP protocol, aborting should ctx be done before
+// the connection is established.
+func (t *TCP) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	if err := t.Validate(); err != nil {
 		return nil, err
 	}
 
-	conn, err := net.DialTCP("tcp", nil, resolved)
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
 	if err != nil {
 		return nil, err
 	}
 
-	conn.SetWriteBuffer(t.WriteBufferSize)
-	conn.SetReadBuffer(t.ReadBufferSize)
-	conn.SetNoDelay(t.NoDelay)
+	t.configure(conn.(*net.TCPConn))
 
 	return conn, nil
 }
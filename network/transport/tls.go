@@ -0,0 +1,268 @@
+package transport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/pkg/errors"
+)
+
+const (
+	// identityScheme is the scheme of the URI a certificate derived from a node's keys
+	// carries, holding the node's public key and its signature of the certificate's key.
+	identityScheme = "noise"
+
+	// identityPrefix separates signatures of certificate keys from other signatures made
+	// with a node's keys.
+	identityPrefix = "noise-tls-identity:"
+
+	// Certificates derived from keys are pinned to the keys rather than verified through
+	// a chain of trust, so their validity period is not checked.
+	certificateValidity = 10 * 365 * 24 * time.Hour
+)
+
+// TLS represents the TLS transport protocol over TCP alongside its respective configurable options.
+type TLS struct {
+	// TCP dials and listens for the connections TLS runs over.
+	TCP *TCP
+
+	// Config configures both ends of connections.
+	Config *tls.Config
+}
+
+// NewTLS instantiates a new instance of the TLS transport protocol, securing connections as
+// configured by config. Config must hold a certificate to listen with, and be able to verify
+// the certificates of peers dialed.
+func NewTLS(config *tls.Config) *TLS {
+	return &TLS{
+		TCP:    NewTCP(),
+		Config: config,
+	}
+}
+
+// NewTLSWithKeys instantiates a new instance of the TLS transport protocol, securing connections
+// with a self-signed certificate derived from a node's ed25519 keys. Peers present certificates
+// derived from their keys in turn, which are verified to be signed by the keys they carry rather
+// than through a chain of trust. See PeerPublicKey.
+func NewTLSWithKeys(keys *crypto.KeyPair) (*TLS, error) {
+	certificate, err := deriveCertificate(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTLS(&tls.Config{
+		Certificates:          []tls.Certificate{certificate},
+		ClientAuth:            tls.RequireAnyClientCert,
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: verifyDerivedCertificate,
+		MinVersion:            tls.VersionTLS12,
+	}), nil
+}
+
+// Listen listens for incoming TLS connections on an address of the form host:port, or on
+// all interfaces over both IPv4 and IPv6 should host be empty.
+func (t *TLS) Listen(address string) (net.Listener, error) {
+	listener, err := t.TCP.Listen(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return tls.NewListener(listener, t.Config), nil
+}
+
+// Dial dials an address via. the TLS protocol.
+func (t *TLS) Dial(address string) (net.Conn, error) {
+	return t.DialContext(context.Background(), address)
+}
+
+// DialContext dials an address via. the TLS protocol, aborting should ctx be done before the
+// TLS handshake completes.
+func (t *TLS) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	raw, err := t.TCP.DialContext(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	config := t.Config
+	if len(config.ServerName) == 0 && !config.InsecureSkipVerify {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			raw.Close()
+			return nil, err
+		}
+
+		config = config.Clone()
+		config.ServerName = host
+	}
+
+	conn := tls.Client(raw, config)
+
+	// Handshakes may not be interrupted, so abort the handshake by closing its connection.
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			raw.Close()
+		case <-done:
+		}
+	}()
+
+	err = conn.Handshake()
+	close(done)
+
+	if err != nil {
+		raw.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// PeerPublicKey returns the public key of the node at the other end of a TLS connection,
+// should the node have presented a certificate derived from its keys. Returns false should
+// conn not be a TLS connection secured as such.
+func PeerPublicKey(conn net.Conn) ([]byte, bool) {
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return nil, false
+	}
+
+	certificates := tlsConn.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return nil, false
+	}
+
+	publicKey, _, err := certificateIdentity(certificates[0])
+	if err != nil {
+		return nil, false
+	}
+
+	return publicKey, true
+}
+
+// deriveCertificate creates a self-signed certificate whose key is signed by keys. As TLS
+// may not authenticate with ed25519 keys directly, the certificate's key is an ECDSA key
+// generated on the fly.
+func deriveCertificate(keys *crypto.KeyPair) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	publicKeyInfo, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	signature := ed25519.New().Sign(keys.PrivateKey, append([]byte(identityPrefix), publicKeyInfo...))
+	if len(signature) == 0 {
+		return tls.Certificate{}, errors.New("transport: keys are not ed25519 keys")
+	}
+
+	return createCertificate(key, keys.PublicKey, signature)
+}
+
+// createCertificate creates a self-signed certificate for key, carrying a node's public key
+// and its signature of key.
+func createCertificate(key *ecdsa.PrivateKey, publicKey []byte, signature []byte) (tls.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: hex.EncodeToString(publicKey)},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certificateValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		URIs: []*url.URL{{
+			Scheme: identityScheme,
+			Opaque: hex.EncodeToString(publicKey) + ":" + hex.EncodeToString(signature),
+		}},
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	return tls.Certificate{Certificate: [][]byte{raw}, PrivateKey: key}, nil
+}
+
+// certificateIdentity returns the public key and signature carried by a certificate derived
+// from a node's keys.
+func certificateIdentity(certificate *x509.Certificate) ([]byte, []byte, error) {
+	for _, uri := range certificate.URIs {
+		if uri.Scheme != identityScheme {
+			continue
+		}
+
+		fields := strings.Split(uri.Opaque, ":")
+		if len(fields) != 2 {
+			break
+		}
+
+		publicKey, err := hex.DecodeString(fields[0])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		signature, err := hex.DecodeString(fields[1])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return publicKey, signature, nil
+	}
+
+	return nil, nil, errors.New("transport: certificate carries no node identity")
+}
+
+// verifyDerivedCertificate verifies that the certificate presented by a peer is derived
+// from the keys it carries.
+func verifyDerivedCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) != 1 {
+		return errors.New("transport: peer must present a single certificate")
+	}
+
+	certificate, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return err
+	}
+
+	publicKey, signature, err := certificateIdentity(certificate)
+	if err != nil {
+		return err
+	}
+
+	policy := ed25519.New()
+	if len(publicKey) != policy.PublicKeySize() {
+		return errors.New("transport: certificate carries a public key of invalid length")
+	}
+
+	if !policy.Verify(publicKey, append([]byte(identityPrefix), certificate.RawSubjectPublicKeyInfo...), signature) {
+		return errors.New("transport: certificate is not signed by the keys it carries")
+	}
+
+	return nil
+}
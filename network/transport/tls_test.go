@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/perlin-network/noise/crypto/ed25519"
+)
+
+func TestTLSWithKeys(t *testing.T) {
+	t.Parallel()
+
+	serverKeys, clientKeys := ed25519.RandomKeyPair(), ed25519.RandomKeyPair()
+
+	server, err := NewTLSWithKeys(serverKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewTLSWithKeys(clientKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := server.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			accepted <- nil
+			return
+		}
+		io.Copy(conn, io.LimitReader(conn, 5))
+		accepted <- conn
+	}()
+
+	conn, err := client.Dial(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if publicKey, derived := PeerPublicKey(conn); !derived || !bytes.Equal(publicKey, serverKeys.PublicKey) {
+		t.Errorf("expected the server to be identified by its public key, got %x", publicKey)
+	}
+
+	if _, err := conn.Write([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	echo := make([]byte, 5)
+	if _, err := io.ReadFull(conn, echo); err != nil || string(echo) != "hello" {
+		t.Fatalf("expected message to be echoed back, got %q (err=%v)", echo, err)
+	}
+
+	remote := <-accepted
+	if remote == nil {
+		t.Fatal("expected connection to be accepted")
+	}
+	defer remote.Close()
+
+	if publicKey, derived := PeerPublicKey(remote); !derived || !bytes.Equal(publicKey, clientKeys.PublicKey) {
+		t.Errorf("expected the client to be identified by its public key, got %x", publicKey)
+	}
+}
+
+func TestVerifyDerivedCertificate(t *testing.T) {
+	t.Parallel()
+
+	keys, other := ed25519.RandomKeyPair(), ed25519.RandomKeyPair()
+
+	certificate, err := deriveCertificate(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verifyDerivedCertificate(certificate.Certificate, nil); err != nil {
+		t.Fatalf("expected certificate derived from keys to be verified, got %v", err)
+	}
+
+	// Claim to be another node, without holding its keys.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKeyInfo, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := ed25519.New().Sign(keys.PrivateKey, append([]byte(identityPrefix), publicKeyInfo...))
+
+	forged, err := createCertificate(key, other.PublicKey, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verifyDerivedCertificate(forged.Certificate, nil); err == nil {
+		t.Error("expected certificate not signed by the keys it carries to be rejected")
+	}
+}
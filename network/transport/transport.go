@@ -1,8 +1,16 @@
 package transport
 
-import "net"
+import (
+	"context"
+	"net"
+)
 
 type Layer interface {
-	Listen(port int) (net.Listener, error)
+	// Listen listens on an address of the form host:port. Should host be empty, all
+	// interfaces are listened on over both IPv4 and IPv6. Layers of path-based protocols
+	// are given solely a path instead, and layers of protocols whose addresses carry a
+	// path are given host:port followed by the path.
+	Listen(address string) (net.Listener, error)
 	Dial(address string) (net.Conn, error)
+	DialContext(ctx context.Context, address string) (net.Conn, error)
 }
@@ -0,0 +1,42 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"os"
+)
+
+// Unix represents the Unix domain socket transport protocol, connecting nodes and sidecars
+// residing on the same host without going through the network stack.
+type Unix struct{}
+
+// NewUnix instantiates a new instance of the Unix domain socket transport protocol.
+func NewUnix() *Unix {
+	return new(Unix)
+}
+
+// Listen listens for incoming connections on the Unix domain socket at a path. Sockets left
+// behind by listeners which did not shut down cleanly are removed.
+func (t *Unix) Listen(path string) (net.Listener, error) {
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+		} else {
+			os.Remove(path)
+		}
+	}
+
+	return net.Listen("unix", path)
+}
+
+// Dial dials the Unix domain socket at a path.
+func (t *Unix) Dial(path string) (net.Conn, error) {
+	return t.DialContext(context.Background(), path)
+}
+
+// DialContext dials the Unix domain socket at a path, aborting should ctx be done before
+// the connection is established.
+func (t *Unix) DialContext(ctx context.Context, path string) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "unix", path)
+}
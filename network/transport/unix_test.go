@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnix(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "noise")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "noise.sock")
+
+	// Leave a socket behind as though a listener did not shut down cleanly.
+	stale, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.SetUnlinkOnClose(false)
+	stale.Close()
+
+	layer := NewUnix()
+
+	listener, err := layer.Listen(path)
+	if err != nil {
+		t.Fatalf("expected sockets left behind to be replaced: %+v", err)
+	}
+	defer listener.Close()
+
+	if _, err := layer.Listen(path); err == nil {
+		t.Error("expected listening on a socket in use to fail")
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("ping"))
+			conn.Close()
+		}
+	}()
+
+	conn, err := layer.Dial(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if data, err := ioutil.ReadAll(conn); err != nil || string(data) != "ping" {
+		t.Errorf("expected to read ping, got %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,212 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+)
+
+// WebSocket represents the WebSocket transport protocol over TCP, such that nodes may be
+// reached by browsers and through HTTP gateways. Messages are sent as binary frames.
+type WebSocket struct {
+	// TCP dials and listens for the connections WebSockets run over.
+	TCP *TCP
+
+	// Origin is the origin presented by WebSockets dialed, or http:// followed by the host
+	// and port dialed should it be empty.
+	Origin string
+}
+
+// NewWebSocket instantiates a new instance of the WebSocket transport protocol.
+func NewWebSocket() *WebSocket {
+	return &WebSocket{
+		TCP: NewTCP(),
+	}
+}
+
+// splitWebSocketAddress splits an address of the form host:port/path into host:port and
+// its path, which defaults to the root.
+func splitWebSocketAddress(address string) (string, string) {
+	if i := strings.Index(address, "/"); i >= 0 {
+		return address[:i], address[i:]
+	}
+	return address, "/"
+}
+
+// Listen listens for incoming WebSockets upgraded from HTTP requests to a path, on an address
+// of the form host:port/path. Should host be empty, all interfaces are listened on over both
+// IPv4 and IPv6.
+func (t *WebSocket) Listen(address string) (net.Listener, error) {
+	hostPort, path := splitWebSocketAddress(address)
+
+	listener, err := t.TCP.Listen(hostPort)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &webSocketListener{
+		Listener: listener,
+		conns:    make(chan net.Conn),
+		done:     make(chan struct{}),
+	}
+
+	// Browsers always present an origin, whereas nodes may not, so origins are not checked.
+	mux := http.NewServeMux()
+	mux.Handle(path, websocket.Server{Handler: l.handle})
+	l.server = &http.Server{Handler: mux}
+
+	go func() {
+		if err := l.server.Serve(listener); err != http.ErrServerClosed {
+			l.fail(err)
+		}
+	}()
+
+	return l, nil
+}
+
+// Dial dials an address of the form host:port/path via. the WebSocket protocol.
+func (t *WebSocket) Dial(address string) (net.Conn, error) {
+	return t.DialContext(context.Background(), address)
+}
+
+// DialContext dials an address of the form host:port/path via. the WebSocket protocol,
+// aborting should ctx be done before the WebSocket handshake completes.
+func (t *WebSocket) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	hostPort, path := splitWebSocketAddress(address)
+
+	origin := t.Origin
+	if len(origin) == 0 {
+		origin = "http://" + hostPort
+	}
+
+	config, err := websocket.NewConfig("ws://"+hostPort+path, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := t.TCP.DialContext(ctx, hostPort)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handshakes may not be interrupted, so abort the handshake by closing its connection.
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			raw.Close()
+		case <-done:
+		}
+	}()
+
+	ws, err := websocket.NewClient(config, raw)
+	close(done)
+
+	if err != nil {
+		raw.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+
+	return newWebSocketConn(ws, raw.LocalAddr(), raw.RemoteAddr()), nil
+}
+
+// webSocketListener accepts the WebSockets upgraded from HTTP requests served by an HTTP
+// server.
+type webSocketListener struct {
+	net.Listener
+
+	server *http.Server
+	conns  chan net.Conn
+
+	done     chan struct{}
+	doneOnce sync.Once
+	err      error
+}
+
+// handle hands a WebSocket over to Accept, holding on to it until it is closed as the
+// WebSocket is closed once handle returns.
+func (l *webSocketListener) handle(ws *websocket.Conn) {
+	var remote net.Addr = l.Addr()
+	if addr, err := net.ResolveTCPAddr("tcp", ws.Request().RemoteAddr); err == nil {
+		remote = addr
+	}
+
+	conn := newWebSocketConn(ws, l.Addr(), remote)
+
+	select {
+	case l.conns <- conn:
+		<-conn.closed
+	case <-l.done:
+	}
+}
+
+// Accept implements net.Listener.
+func (l *webSocketListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, l.err
+	}
+}
+
+// Close implements net.Listener, shutting down the HTTP server WebSockets are upgraded by.
+func (l *webSocketListener) Close() error {
+	l.fail(errors.New("transport: websocket listener closed"))
+	return l.server.Close()
+}
+
+func (l *webSocketListener) fail(err error) {
+	l.doneOnce.Do(func() {
+		l.err = err
+		close(l.done)
+	})
+}
+
+// webSocketConn represents a WebSocket carrying binary frames, addressed by the addresses
+// of the TCP connection it runs over.
+type webSocketConn struct {
+	*websocket.Conn
+
+	local, remote net.Addr
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newWebSocketConn(ws *websocket.Conn, local net.Addr, remote net.Addr) *webSocketConn {
+	ws.PayloadType = websocket.BinaryFrame
+
+	return &webSocketConn{
+		Conn:   ws,
+		local:  local,
+		remote: remote,
+		closed: make(chan struct{}),
+	}
+}
+
+// Close implements net.Conn.
+func (c *webSocketConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return c.Conn.Close()
+}
+
+// LocalAddr implements net.Conn.
+func (c *webSocketConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+// RemoteAddr implements net.Conn.
+func (c *webSocketConn) RemoteAddr() net.Addr {
+	return c.remote
+}
@@ -0,0 +1,65 @@
+package transport
+
+import (
+	"io"
+	"net"
+	"testing"
+)
+
+func TestWebSocket(t *testing.T) {
+	t.Parallel()
+
+	layer := NewWebSocket()
+
+	listener, err := layer.Listen("127.0.0.1:0/noise")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	address := listener.Addr().String()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			accepted <- nil
+			return
+		}
+		accepted <- conn
+	}()
+
+	if _, err := layer.Dial(address + "/other"); err == nil {
+		t.Error("expected dialing a path not listened on to fail")
+	}
+
+	client, err := layer.Dial(address + "/noise")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	server := <-accepted
+	if server == nil {
+		t.Fatal("expected listener to accept the websocket dialed")
+	}
+	defer server.Close()
+
+	if server.RemoteAddr().String() != client.LocalAddr().String() {
+		t.Errorf("expected ends of the websocket to agree on addresses, got %s and %s", server.RemoteAddr(), client.LocalAddr())
+	}
+
+	// Writes are read as a stream regardless of the frames they are sent in.
+	client.Write([]byte("pi"))
+	client.Write([]byte("ng"))
+
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(server, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("expected server to read ping, got %q (%v)", buf, err)
+	}
+
+	server.Write([]byte("pong"))
+	if _, err := io.ReadFull(client, buf); err != nil || string(buf) != "pong" {
+		t.Errorf("expected client to read pong, got %q (%v)", buf, err)
+	}
+}
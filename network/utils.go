@@ -11,7 +11,12 @@ import (
 func SerializeMessage(id *protobuf.ID, message []byte) []byte {
 	const uint32Size = 4
 
-	serialized := make([]byte, uint32Size+len(id.Address)+uint32Size+len(id.PublicKey)+len(message))
+	size := uint32Size + len(id.Address) + uint32Size + len(id.PublicKey) + len(message)
+	for _, address := range id.Addresses {
+		size += uint32Size + len(address)
+	}
+
+	serialized := make([]byte, size)
 	pos := 0
 
 	binary.LittleEndian.PutUint32(serialized[pos:], uint32(len(id.Address)))
@@ -26,6 +31,16 @@ func SerializeMessage(id *protobuf.ID, message []byte) []byte {
 	copy(serialized[pos:], id.PublicKey)
 	pos += len(id.PublicKey)
 
+	// Further addresses are only serialized when advertised, such that messages
+	// of nodes listening on a single address are signed as before.
+	for _, address := range id.Addresses {
+		binary.LittleEndian.PutUint32(serialized[pos:], uint32(len(address)))
+		pos += uint32Size
+
+		copy(serialized[pos:], []byte(address))
+		pos += len(address)
+	}
+
 	copy(serialized[pos:], message)
 	pos += len(message)
 
@@ -36,6 +51,20 @@ func SerializeMessage(id *protobuf.ID, message []byte) []byte {
 	return serialized
 }
 
+// serializeAuthenticated packs all bytes of a message covered by its signature or MAC, being
+// those packed by SerializeMessage followed by the nonces and flags of the message, such that
+// the message may not be replayed under other nonces.
+func serializeAuthenticated(msg *protobuf.Message) []byte {
+	var header [17]byte
+	binary.BigEndian.PutUint64(header[0:8], msg.RequestNonce)
+	binary.BigEndian.PutUint64(header[8:16], msg.MessageNonce)
+	if msg.ReplyFlag {
+		header[16] = 1
+	}
+
+	return append(SerializeMessage(msg.Sender, msg.Message.Value), header[:]...)
+}
+
 // FilterPeers filters out duplicate/empty addresses.
 func FilterPeers(address string, peers []string) (filtered []string) {
 	visited := make(map[string]struct{})
@@ -64,3 +93,13 @@ func GetRandomUnusedPort() int {
 	defer listener.Close()
 	return listener.Addr().(*net.TCPAddr).Port
 }
+
+// containsString returns true should values contain value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
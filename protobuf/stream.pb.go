@@ -28,8 +28,11 @@ var _ = math.Inf
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
 type ID struct {
-	PublicKey            []byte   `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
-	Address              string   `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	PublicKey []byte `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Address   string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	// addresses are the further addresses the node listens on besides its
+	// primary address, over which it may be dialed as well.
+	Addresses            []string `protobuf:"bytes,3,rep,name=addresses" json:"addresses,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
@@ -37,7 +40,7 @@ type ID struct {
 func (m *ID) Reset()      { *m = ID{} }
 func (*ID) ProtoMessage() {}
 func (*ID) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_943b2a05a2172ca3, []int{0}
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{0}
 }
 func (m *ID) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -80,6 +83,13 @@ func (m *ID) GetAddress() string {
 	return ""
 }
 
+func (m *ID) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
 type Message struct {
 	Message *types.Any `protobuf:"bytes,1,opt,name=message" json:"message,omitempty"`
 	// Sender's address and public key.
@@ -91,7 +101,10 @@ type Message struct {
 	// message_nonce is the sequence ID.
 	MessageNonce uint64 `protobuf:"varint,5,opt,name=message_nonce,json=messageNonce,proto3" json:"message_nonce,omitempty"`
 	// reply_flag indicates this is a reply to a request
-	ReplyFlag            bool     `protobuf:"varint,6,opt,name=reply_flag,json=replyFlag,proto3" json:"reply_flag,omitempty"`
+	ReplyFlag bool `protobuf:"varint,6,opt,name=reply_flag,json=replyFlag,proto3" json:"reply_flag,omitempty"`
+	// Sender's session MAC of message. Set in place of a signature when the
+	// message is only to be verified by the peer it was sent to.
+	Mac                  []byte   `protobuf:"bytes,7,opt,name=mac,proto3" json:"mac,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
@@ -99,7 +112,7 @@ type Message struct {
 func (m *Message) Reset()      { *m = Message{} }
 func (*Message) ProtoMessage() {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_943b2a05a2172ca3, []int{1}
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{1}
 }
 func (m *Message) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -170,6 +183,13 @@ func (m *Message) GetReplyFlag() bool {
 	return false
 }
 
+func (m *Message) GetMac() []byte {
+	if m != nil {
+		return m.Mac
+	}
+	return nil
+}
+
 type Ping struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -178,7 +198,7 @@ type Ping struct {
 func (m *Ping) Reset()      { *m = Ping{} }
 func (*Ping) ProtoMessage() {}
 func (*Ping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_943b2a05a2172ca3, []int{2}
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{2}
 }
 func (m *Ping) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -215,7 +235,7 @@ type Pong struct {
 func (m *Pong) Reset()      { *m = Pong{} }
 func (*Pong) ProtoMessage() {}
 func (*Pong) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_943b2a05a2172ca3, []int{3}
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{3}
 }
 func (m *Pong) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -253,7 +273,7 @@ type LookupNodeRequest struct {
 func (m *LookupNodeRequest) Reset()      { *m = LookupNodeRequest{} }
 func (*LookupNodeRequest) ProtoMessage() {}
 func (*LookupNodeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_943b2a05a2172ca3, []int{4}
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{4}
 }
 func (m *LookupNodeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -298,7 +318,7 @@ type LookupNodeResponse struct {
 func (m *LookupNodeResponse) Reset()      { *m = LookupNodeResponse{} }
 func (*LookupNodeResponse) ProtoMessage() {}
 func (*LookupNodeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_943b2a05a2172ca3, []int{5}
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{5}
 }
 func (m *LookupNodeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -343,7 +363,7 @@ type Bytes struct {
 func (m *Bytes) Reset()      { *m = Bytes{} }
 func (*Bytes) ProtoMessage() {}
 func (*Bytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_stream_943b2a05a2172ca3, []int{6}
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{6}
 }
 func (m *Bytes) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -379,160 +399,702 @@ func (m *Bytes) GetData() []byte {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*ID)(nil), "protobuf.ID")
-	proto.RegisterType((*Message)(nil), "protobuf.Message")
-	proto.RegisterType((*Ping)(nil), "protobuf.Ping")
-	proto.RegisterType((*Pong)(nil), "protobuf.Pong")
-	proto.RegisterType((*LookupNodeRequest)(nil), "protobuf.LookupNodeRequest")
-	proto.RegisterType((*LookupNodeResponse)(nil), "protobuf.LookupNodeResponse")
-	proto.RegisterType((*Bytes)(nil), "protobuf.Bytes")
+// HandshakeRequest challenges a newly connected peer to prove possession of
+// the private key belonging to the ID it advertises. It doubles as a hello,
+// advertising the protocol version and features of the sender.
+type HandshakeRequest struct {
+	Challenge []byte `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	// compressors are the names of the compression algorithms supported by the
+	// sender, in order of preference.
+	Compressors []string `protobuf:"bytes,2,rep,name=compressors" json:"compressors,omitempty"`
+	// version is the protocol version spoken by the sender.
+	Version uint32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// codec is the name of the codec the sender encodes messages with.
+	Codec string `protobuf:"bytes,4,opt,name=codec,proto3" json:"codec,omitempty"`
+	// features are the names of the optional features supported by the sender.
+	Features []string `protobuf:"bytes,5,rep,name=features" json:"features,omitempty"`
+	// probe is set if the sender only wishes to verify the identity of the
+	// peer residing at the address it dialed, rather than to connect to it.
+	Probe                bool     `protobuf:"varint,6,opt,name=probe,proto3" json:"probe,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (this *ID) VerboseEqual(that interface{}) error {
-	if that == nil {
-		if this == nil {
-			return nil
+
+func (m *HandshakeRequest) Reset()      { *m = HandshakeRequest{} }
+func (*HandshakeRequest) ProtoMessage() {}
+func (*HandshakeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{7}
+}
+func (m *HandshakeRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HandshakeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HandshakeRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
 		}
-		return fmt.Errorf("that == nil && this != nil")
+		return b[:n], nil
 	}
+}
+func (dst *HandshakeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HandshakeRequest.Merge(dst, src)
+}
+func (m *HandshakeRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *HandshakeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HandshakeRequest.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*ID)
-	if !ok {
-		that2, ok := that.(ID)
-		if ok {
-			that1 = &that2
-		} else {
-			return fmt.Errorf("that is not of type *ID")
-		}
+var xxx_messageInfo_HandshakeRequest proto.InternalMessageInfo
+
+func (m *HandshakeRequest) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
 	}
-	if that1 == nil {
-		if this == nil {
-			return nil
-		}
-		return fmt.Errorf("that is type *ID but is nil && this != nil")
-	} else if this == nil {
-		return fmt.Errorf("that is type *ID but is not nil && this == nil")
+	return nil
+}
+
+func (m *HandshakeRequest) GetCompressors() []string {
+	if m != nil {
+		return m.Compressors
 	}
-	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
-		return fmt.Errorf("PublicKey this(%v) Not Equal that(%v)", this.PublicKey, that1.PublicKey)
+	return nil
+}
+
+func (m *HandshakeRequest) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
 	}
-	if this.Address != that1.Address {
-		return fmt.Errorf("Address this(%v) Not Equal that(%v)", this.Address, that1.Address)
+	return 0
+}
+
+func (m *HandshakeRequest) GetCodec() string {
+	if m != nil {
+		return m.Codec
+	}
+	return ""
+}
+
+func (m *HandshakeRequest) GetFeatures() []string {
+	if m != nil {
+		return m.Features
 	}
 	return nil
 }
-func (this *ID) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *HandshakeRequest) GetProbe() bool {
+	if m != nil {
+		return m.Probe
 	}
+	return false
+}
 
-	that1, ok := that.(*ID)
-	if !ok {
-		that2, ok := that.(ID)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
+// HandshakeResponse echoes back the challenge of a HandshakeRequest. As it is
+// signed by its sender, it proves possession of the sender's private key.
+type HandshakeResponse struct {
+	Challenge []byte `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"`
+	// ephemeral_key is the sender's ephemeral X25519 public key, from which
+	// the keys of the session are derived.
+	EphemeralKey []byte `protobuf:"bytes,2,opt,name=ephemeral_key,json=ephemeralKey,proto3" json:"ephemeral_key,omitempty"`
+	// encrypted is set if the sender wishes to encrypt the session.
+	Encrypted            bool     `protobuf:"varint,3,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HandshakeResponse) Reset()      { *m = HandshakeResponse{} }
+func (*HandshakeResponse) ProtoMessage() {}
+func (*HandshakeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{8}
+}
+func (m *HandshakeResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *HandshakeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_HandshakeResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
 		}
+		return b[:n], nil
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+}
+func (dst *HandshakeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HandshakeResponse.Merge(dst, src)
+}
+func (m *HandshakeResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *HandshakeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HandshakeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HandshakeResponse proto.InternalMessageInfo
+
+func (m *HandshakeResponse) GetChallenge() []byte {
+	if m != nil {
+		return m.Challenge
 	}
-	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
-		return false
+	return nil
+}
+
+func (m *HandshakeResponse) GetEphemeralKey() []byte {
+	if m != nil {
+		return m.EphemeralKey
 	}
-	if this.Address != that1.Address {
-		return false
+	return nil
+}
+
+func (m *HandshakeResponse) GetEncrypted() bool {
+	if m != nil {
+		return m.Encrypted
 	}
-	return true
+	return false
 }
-func (this *Message) VerboseEqual(that interface{}) error {
-	if that == nil {
-		if this == nil {
-			return nil
+
+// Ack acknowledges the receipt of all messages sent over a connection up to
+// and including the message numbered nonce.
+type Ack struct {
+	Nonce                uint64   `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Ack) Reset()      { *m = Ack{} }
+func (*Ack) ProtoMessage() {}
+func (*Ack) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{9}
+}
+func (m *Ack) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Ack) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Ack.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
 		}
-		return fmt.Errorf("that == nil && this != nil")
+		return b[:n], nil
 	}
+}
+func (dst *Ack) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ack.Merge(dst, src)
+}
+func (m *Ack) XXX_Size() int {
+	return m.Size()
+}
+func (m *Ack) XXX_DiscardUnknown() {
+	xxx_messageInfo_Ack.DiscardUnknown(m)
+}
 
-	that1, ok := that.(*Message)
-	if !ok {
-		that2, ok := that.(Message)
-		if ok {
-			that1 = &that2
-		} else {
-			return fmt.Errorf("that is not of type *Message")
-		}
+var xxx_messageInfo_Ack proto.InternalMessageInfo
+
+func (m *Ack) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
 	}
-	if that1 == nil {
-		if this == nil {
-			return nil
+	return 0
+}
+
+// Goodbye tells a peer that the sender is shutting down, and that it should
+// no longer be sent new messages.
+type Goodbye struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Goodbye) Reset()      { *m = Goodbye{} }
+func (*Goodbye) ProtoMessage() {}
+func (*Goodbye) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{10}
+}
+func (m *Goodbye) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Goodbye) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Goodbye.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
 		}
-		return fmt.Errorf("that is type *Message but is nil && this != nil")
-	} else if this == nil {
-		return fmt.Errorf("that is type *Message but is not nil && this == nil")
-	}
-	if !this.Message.Equal(that1.Message) {
-		return fmt.Errorf("Message this(%v) Not Equal that(%v)", this.Message, that1.Message)
-	}
-	if !this.Sender.Equal(that1.Sender) {
-		return fmt.Errorf("Sender this(%v) Not Equal that(%v)", this.Sender, that1.Sender)
+		return b[:n], nil
 	}
-	if !bytes.Equal(this.Signature, that1.Signature) {
-		return fmt.Errorf("Signature this(%v) Not Equal that(%v)", this.Signature, that1.Signature)
+}
+func (dst *Goodbye) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Goodbye.Merge(dst, src)
+}
+func (m *Goodbye) XXX_Size() int {
+	return m.Size()
+}
+func (m *Goodbye) XXX_DiscardUnknown() {
+	xxx_messageInfo_Goodbye.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Goodbye proto.InternalMessageInfo
+
+// Error is replied in place of a response to a request which failed to be
+// handled by the remote peer.
+type Error struct {
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// code is an application-defined code categorizing the failure.
+	Code uint32 `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	// details optionally hold a message describing the failure further.
+	Details              *types.Any `protobuf:"bytes,3,opt,name=details" json:"details,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *Error) Reset()      { *m = Error{} }
+func (*Error) ProtoMessage() {}
+func (*Error) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{11}
+}
+func (m *Error) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Error) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Error.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if this.RequestNonce != that1.RequestNonce {
-		return fmt.Errorf("RequestNonce this(%v) Not Equal that(%v)", this.RequestNonce, that1.RequestNonce)
+}
+func (dst *Error) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Error.Merge(dst, src)
+}
+func (m *Error) XXX_Size() int {
+	return m.Size()
+}
+func (m *Error) XXX_DiscardUnknown() {
+	xxx_messageInfo_Error.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Error proto.InternalMessageInfo
+
+func (m *Error) GetMessage() string {
+	if m != nil {
+		return m.Message
 	}
-	if this.MessageNonce != that1.MessageNonce {
-		return fmt.Errorf("MessageNonce this(%v) Not Equal that(%v)", this.MessageNonce, that1.MessageNonce)
+	return ""
+}
+
+func (m *Error) GetCode() uint32 {
+	if m != nil {
+		return m.Code
 	}
-	if this.ReplyFlag != that1.ReplyFlag {
-		return fmt.Errorf("ReplyFlag this(%v) Not Equal that(%v)", this.ReplyFlag, that1.ReplyFlag)
+	return 0
+}
+
+func (m *Error) GetDetails() *types.Any {
+	if m != nil {
+		return m.Details
 	}
 	return nil
 }
-func (this *Message) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
 
-	that1, ok := that.(*Message)
-	if !ok {
-		that2, ok := that.(Message)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
-	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+// StreamOpen opens a request stream numbered by its request nonce, carrying
+// the first request of the stream.
+type StreamOpen struct {
+	Message              *types.Any `protobuf:"bytes,1,opt,name=message" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *StreamOpen) Reset()      { *m = StreamOpen{} }
+func (*StreamOpen) ProtoMessage() {}
+func (*StreamOpen) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{12}
+}
+func (m *StreamOpen) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamOpen) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamOpen.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if !this.Message.Equal(that1.Message) {
-		return false
+}
+func (dst *StreamOpen) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamOpen.Merge(dst, src)
+}
+func (m *StreamOpen) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamOpen) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamOpen.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamOpen proto.InternalMessageInfo
+
+func (m *StreamOpen) GetMessage() *types.Any {
+	if m != nil {
+		return m.Message
 	}
-	if !this.Sender.Equal(that1.Sender) {
-		return false
+	return nil
+}
+
+// StreamEnd signals that its sender is done sending messages over a request
+// stream.
+type StreamEnd struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamEnd) Reset()      { *m = StreamEnd{} }
+func (*StreamEnd) ProtoMessage() {}
+func (*StreamEnd) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{13}
+}
+func (m *StreamEnd) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamEnd) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamEnd.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if !bytes.Equal(this.Signature, that1.Signature) {
-		return false
+}
+func (dst *StreamEnd) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamEnd.Merge(dst, src)
+}
+func (m *StreamEnd) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamEnd) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamEnd.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamEnd proto.InternalMessageInfo
+
+// StreamCancel aborts a request stream.
+type StreamCancel struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamCancel) Reset()      { *m = StreamCancel{} }
+func (*StreamCancel) ProtoMessage() {}
+func (*StreamCancel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{14}
+}
+func (m *StreamCancel) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamCancel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamCancel.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if this.RequestNonce != that1.RequestNonce {
-		return false
+}
+func (dst *StreamCancel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamCancel.Merge(dst, src)
+}
+func (m *StreamCancel) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamCancel) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamCancel.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamCancel proto.InternalMessageInfo
+
+// Chunk is a fragment of a message too large to be sent in a single frame.
+// The fragments of a message share a transfer ID unique to the connection
+// they are sent over, and are sent in order.
+type Chunk struct {
+	Transfer uint64 `protobuf:"varint,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	Index    uint32 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Total    uint32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Data     []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// length is the length in bytes of the reassembled message.
+	Length uint64 `protobuf:"varint,5,opt,name=length,proto3" json:"length,omitempty"`
+	// hash is the hash of the reassembled message under the sender's hash
+	// policy.
+	Hash                 []byte   `protobuf:"bytes,6,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Chunk) Reset()      { *m = Chunk{} }
+func (*Chunk) ProtoMessage() {}
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{15}
+}
+func (m *Chunk) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Chunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Chunk.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
 	}
-	if this.MessageNonce != that1.MessageNonce {
-		return false
+}
+func (dst *Chunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Chunk.Merge(dst, src)
+}
+func (m *Chunk) XXX_Size() int {
+	return m.Size()
+}
+func (m *Chunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_Chunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Chunk proto.InternalMessageInfo
+
+func (m *Chunk) GetTransfer() uint64 {
+	if m != nil {
+		return m.Transfer
 	}
-	if this.ReplyFlag != that1.ReplyFlag {
-		return false
+	return 0
+}
+
+func (m *Chunk) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
 	}
-	return true
+	return 0
 }
-func (this *Ping) VerboseEqual(that interface{}) error {
+
+func (m *Chunk) GetTotal() uint32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *Chunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Chunk) GetLength() uint64 {
+	if m != nil {
+		return m.Length
+	}
+	return 0
+}
+
+func (m *Chunk) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+// StreamFrame carries the data and control flags of a stream multiplexed
+// over the messages exchanged with a peer.
+type StreamFrame struct {
+	Stream uint32 `protobuf:"varint,1,opt,name=stream,proto3" json:"stream,omitempty"`
+	// initiator is set if the stream was opened by the sender of the frame.
+	Initiator bool `protobuf:"varint,2,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	// open opens a new stream.
+	Open bool   `protobuf:"varint,3,opt,name=open,proto3" json:"open,omitempty"`
+	Data []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	// window grants the receiver of the frame room to send as many more bytes
+	// over the stream.
+	Window uint32 `protobuf:"varint,5,opt,name=window,proto3" json:"window,omitempty"`
+	// fin signals that the sender of the frame is done writing to the stream.
+	Fin bool `protobuf:"varint,6,opt,name=fin,proto3" json:"fin,omitempty"`
+	// abort resets the stream.
+	Abort                bool     `protobuf:"varint,7,opt,name=abort,proto3" json:"abort,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamFrame) Reset()      { *m = StreamFrame{} }
+func (*StreamFrame) ProtoMessage() {}
+func (*StreamFrame) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{16}
+}
+func (m *StreamFrame) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamFrame) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamFrame.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *StreamFrame) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamFrame.Merge(dst, src)
+}
+func (m *StreamFrame) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamFrame) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamFrame.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamFrame proto.InternalMessageInfo
+
+func (m *StreamFrame) GetStream() uint32 {
+	if m != nil {
+		return m.Stream
+	}
+	return 0
+}
+
+func (m *StreamFrame) GetInitiator() bool {
+	if m != nil {
+		return m.Initiator
+	}
+	return false
+}
+
+func (m *StreamFrame) GetOpen() bool {
+	if m != nil {
+		return m.Open
+	}
+	return false
+}
+
+func (m *StreamFrame) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *StreamFrame) GetWindow() uint32 {
+	if m != nil {
+		return m.Window
+	}
+	return 0
+}
+
+func (m *StreamFrame) GetFin() bool {
+	if m != nil {
+		return m.Fin
+	}
+	return false
+}
+
+func (m *StreamFrame) GetAbort() bool {
+	if m != nil {
+		return m.Abort
+	}
+	return false
+}
+
+// StreamCredit grants the receiver of a request stream leave to send count
+// more messages over it.
+type StreamCredit struct {
+	Count                uint32   `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamCredit) Reset()      { *m = StreamCredit{} }
+func (*StreamCredit) ProtoMessage() {}
+func (*StreamCredit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_stream_f9fcdbd4ca5a461e, []int{17}
+}
+func (m *StreamCredit) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamCredit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamCredit.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *StreamCredit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamCredit.Merge(dst, src)
+}
+func (m *StreamCredit) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamCredit) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamCredit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamCredit proto.InternalMessageInfo
+
+func (m *StreamCredit) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ID)(nil), "protobuf.ID")
+	proto.RegisterType((*Message)(nil), "protobuf.Message")
+	proto.RegisterType((*Ping)(nil), "protobuf.Ping")
+	proto.RegisterType((*Pong)(nil), "protobuf.Pong")
+	proto.RegisterType((*LookupNodeRequest)(nil), "protobuf.LookupNodeRequest")
+	proto.RegisterType((*LookupNodeResponse)(nil), "protobuf.LookupNodeResponse")
+	proto.RegisterType((*Bytes)(nil), "protobuf.Bytes")
+	proto.RegisterType((*HandshakeRequest)(nil), "protobuf.HandshakeRequest")
+	proto.RegisterType((*HandshakeResponse)(nil), "protobuf.HandshakeResponse")
+	proto.RegisterType((*Ack)(nil), "protobuf.Ack")
+	proto.RegisterType((*Goodbye)(nil), "protobuf.Goodbye")
+	proto.RegisterType((*Error)(nil), "protobuf.Error")
+	proto.RegisterType((*StreamOpen)(nil), "protobuf.StreamOpen")
+	proto.RegisterType((*StreamEnd)(nil), "protobuf.StreamEnd")
+	proto.RegisterType((*StreamCancel)(nil), "protobuf.StreamCancel")
+	proto.RegisterType((*Chunk)(nil), "protobuf.Chunk")
+	proto.RegisterType((*StreamFrame)(nil), "protobuf.StreamFrame")
+	proto.RegisterType((*StreamCredit)(nil), "protobuf.StreamCredit")
+}
+func (this *ID) VerboseEqual(that interface{}) error {
 	if that == nil {
 		if this == nil {
 			return nil
@@ -540,31 +1102,197 @@ func (this *Ping) VerboseEqual(that interface{}) error {
 		return fmt.Errorf("that == nil && this != nil")
 	}
 
-	that1, ok := that.(*Ping)
+	that1, ok := that.(*ID)
 	if !ok {
-		that2, ok := that.(Ping)
+		that2, ok := that.(ID)
 		if ok {
 			that1 = &that2
 		} else {
-			return fmt.Errorf("that is not of type *Ping")
+			return fmt.Errorf("that is not of type *ID")
 		}
 	}
 	if that1 == nil {
 		if this == nil {
 			return nil
 		}
-		return fmt.Errorf("that is type *Ping but is nil && this != nil")
+		return fmt.Errorf("that is type *ID but is nil && this != nil")
 	} else if this == nil {
-		return fmt.Errorf("that is type *Ping but is not nil && this == nil")
+		return fmt.Errorf("that is type *ID but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
+		return fmt.Errorf("PublicKey this(%v) Not Equal that(%v)", this.PublicKey, that1.PublicKey)
+	}
+	if this.Address != that1.Address {
+		return fmt.Errorf("Address this(%v) Not Equal that(%v)", this.Address, that1.Address)
+	}
+	if len(this.Addresses) != len(that1.Addresses) {
+		return fmt.Errorf("Addresses this(%v) Not Equal that(%v)", len(this.Addresses), len(that1.Addresses))
+	}
+	for i := range this.Addresses {
+		if this.Addresses[i] != that1.Addresses[i] {
+			return fmt.Errorf("Addresses this[%v](%v) Not Equal that[%v](%v)", i, this.Addresses[i], i, that1.Addresses[i])
+		}
 	}
 	return nil
 }
-func (this *Ping) Equal(that interface{}) bool {
+func (this *ID) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Ping)
+	that1, ok := that.(*ID)
+	if !ok {
+		that2, ok := that.(ID)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
+		return false
+	}
+	if this.Address != that1.Address {
+		return false
+	}
+	if len(this.Addresses) != len(that1.Addresses) {
+		return false
+	}
+	for i := range this.Addresses {
+		if this.Addresses[i] != that1.Addresses[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *Message) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*Message)
+	if !ok {
+		that2, ok := that.(Message)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Message")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Message but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Message but is not nil && this == nil")
+	}
+	if !this.Message.Equal(that1.Message) {
+		return fmt.Errorf("Message this(%v) Not Equal that(%v)", this.Message, that1.Message)
+	}
+	if !this.Sender.Equal(that1.Sender) {
+		return fmt.Errorf("Sender this(%v) Not Equal that(%v)", this.Sender, that1.Sender)
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return fmt.Errorf("Signature this(%v) Not Equal that(%v)", this.Signature, that1.Signature)
+	}
+	if this.RequestNonce != that1.RequestNonce {
+		return fmt.Errorf("RequestNonce this(%v) Not Equal that(%v)", this.RequestNonce, that1.RequestNonce)
+	}
+	if this.MessageNonce != that1.MessageNonce {
+		return fmt.Errorf("MessageNonce this(%v) Not Equal that(%v)", this.MessageNonce, that1.MessageNonce)
+	}
+	if this.ReplyFlag != that1.ReplyFlag {
+		return fmt.Errorf("ReplyFlag this(%v) Not Equal that(%v)", this.ReplyFlag, that1.ReplyFlag)
+	}
+	if !bytes.Equal(this.Mac, that1.Mac) {
+		return fmt.Errorf("Mac this(%v) Not Equal that(%v)", this.Mac, that1.Mac)
+	}
+	return nil
+}
+func (this *Message) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Message)
+	if !ok {
+		that2, ok := that.(Message)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Message.Equal(that1.Message) {
+		return false
+	}
+	if !this.Sender.Equal(that1.Sender) {
+		return false
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return false
+	}
+	if this.RequestNonce != that1.RequestNonce {
+		return false
+	}
+	if this.MessageNonce != that1.MessageNonce {
+		return false
+	}
+	if this.ReplyFlag != that1.ReplyFlag {
+		return false
+	}
+	if !bytes.Equal(this.Mac, that1.Mac) {
+		return false
+	}
+	return true
+}
+func (this *Ping) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*Ping)
+	if !ok {
+		that2, ok := that.(Ping)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Ping")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Ping but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Ping but is not nil && this == nil")
+	}
+	return nil
+}
+func (this *Ping) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Ping)
 	if !ok {
 		that2, ok := that.(Ping)
 		if ok {
@@ -800,498 +1528,3138 @@ func (this *Bytes) Equal(that interface{}) bool {
 	}
 	return true
 }
-func (this *ID) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 6)
-	s = append(s, "&protobuf.ID{")
-	s = append(s, "PublicKey: "+fmt.Sprintf("%#v", this.PublicKey)+",\n")
-	s = append(s, "Address: "+fmt.Sprintf("%#v", this.Address)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Message) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *HandshakeRequest) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
 	}
-	s := make([]string, 0, 10)
-	s = append(s, "&protobuf.Message{")
-	if this.Message != nil {
-		s = append(s, "Message: "+fmt.Sprintf("%#v", this.Message)+",\n")
+
+	that1, ok := that.(*HandshakeRequest)
+	if !ok {
+		that2, ok := that.(HandshakeRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *HandshakeRequest")
+		}
 	}
-	if this.Sender != nil {
-		s = append(s, "Sender: "+fmt.Sprintf("%#v", this.Sender)+",\n")
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *HandshakeRequest but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *HandshakeRequest but is not nil && this == nil")
 	}
-	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
-	s = append(s, "RequestNonce: "+fmt.Sprintf("%#v", this.RequestNonce)+",\n")
-	s = append(s, "MessageNonce: "+fmt.Sprintf("%#v", this.MessageNonce)+",\n")
-	s = append(s, "ReplyFlag: "+fmt.Sprintf("%#v", this.ReplyFlag)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Ping) GoString() string {
-	if this == nil {
-		return "nil"
+	if !bytes.Equal(this.Challenge, that1.Challenge) {
+		return fmt.Errorf("Challenge this(%v) Not Equal that(%v)", this.Challenge, that1.Challenge)
 	}
-	s := make([]string, 0, 4)
-	s = append(s, "&protobuf.Ping{")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Pong) GoString() string {
-	if this == nil {
-		return "nil"
+	if len(this.Compressors) != len(that1.Compressors) {
+		return fmt.Errorf("Compressors this(%v) Not Equal that(%v)", len(this.Compressors), len(that1.Compressors))
 	}
-	s := make([]string, 0, 4)
-	s = append(s, "&protobuf.Pong{")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *LookupNodeRequest) GoString() string {
-	if this == nil {
-		return "nil"
+	for i := range this.Compressors {
+		if this.Compressors[i] != that1.Compressors[i] {
+			return fmt.Errorf("Compressors this[%v](%v) Not Equal that[%v](%v)", i, this.Compressors[i], i, that1.Compressors[i])
+		}
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&protobuf.LookupNodeRequest{")
-	if this.Target != nil {
-		s = append(s, "Target: "+fmt.Sprintf("%#v", this.Target)+",\n")
+	if this.Version != that1.Version {
+		return fmt.Errorf("Version this(%v) Not Equal that(%v)", this.Version, that1.Version)
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *LookupNodeResponse) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.Codec != that1.Codec {
+		return fmt.Errorf("Codec this(%v) Not Equal that(%v)", this.Codec, that1.Codec)
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&protobuf.LookupNodeResponse{")
-	if this.Peers != nil {
-		s = append(s, "Peers: "+fmt.Sprintf("%#v", this.Peers)+",\n")
+	if len(this.Features) != len(that1.Features) {
+		return fmt.Errorf("Features this(%v) Not Equal that(%v)", len(this.Features), len(that1.Features))
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Bytes) GoString() string {
-	if this == nil {
-		return "nil"
+	for i := range this.Features {
+		if this.Features[i] != that1.Features[i] {
+			return fmt.Errorf("Features this[%v](%v) Not Equal that[%v](%v)", i, this.Features[i], i, that1.Features[i])
+		}
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&protobuf.Bytes{")
-	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func valueToGoStringStream(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+	if this.Probe != that1.Probe {
+		return fmt.Errorf("Probe this(%v) Not Equal that(%v)", this.Probe, that1.Probe)
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+	return nil
 }
-func (m *ID) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (this *HandshakeRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	return dAtA[:n], nil
-}
 
-func (m *ID) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.PublicKey) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(len(m.PublicKey)))
-		i += copy(dAtA[i:], m.PublicKey)
+	that1, ok := that.(*HandshakeRequest)
+	if !ok {
+		that2, ok := that.(HandshakeRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if len(m.Address) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(len(m.Address)))
-		i += copy(dAtA[i:], m.Address)
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	return i, nil
-}
-
-func (m *Message) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	if !bytes.Equal(this.Challenge, that1.Challenge) {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Message) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Message != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(m.Message.Size()))
-		n1, err := m.Message.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n1
+	if len(this.Compressors) != len(that1.Compressors) {
+		return false
 	}
-	if m.Sender != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(m.Sender.Size()))
-		n2, err := m.Sender.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	for i := range this.Compressors {
+		if this.Compressors[i] != that1.Compressors[i] {
+			return false
 		}
-		i += n2
 	}
-	if len(m.Signature) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(len(m.Signature)))
-		i += copy(dAtA[i:], m.Signature)
+	if this.Version != that1.Version {
+		return false
 	}
-	if m.RequestNonce != 0 {
-		dAtA[i] = 0x20
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(m.RequestNonce))
+	if this.Codec != that1.Codec {
+		return false
 	}
-	if m.MessageNonce != 0 {
-		dAtA[i] = 0x28
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(m.MessageNonce))
+	if len(this.Features) != len(that1.Features) {
+		return false
 	}
-	if m.ReplyFlag {
-		dAtA[i] = 0x30
-		i++
-		if m.ReplyFlag {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
+	for i := range this.Features {
+		if this.Features[i] != that1.Features[i] {
+			return false
 		}
-		i++
 	}
-	return i, nil
-}
-
-func (m *Ping) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	if this.Probe != that1.Probe {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Ping) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	return i, nil
+	return true
 }
-
-func (m *Pong) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (this *HandshakeResponse) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Pong) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	return i, nil
-}
 
-func (m *LookupNodeRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	that1, ok := that.(*HandshakeResponse)
+	if !ok {
+		that2, ok := that.(HandshakeResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *HandshakeResponse")
+		}
 	}
-	return dAtA[:n], nil
-}
-
-func (m *LookupNodeRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Target != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(m.Target.Size()))
-		n3, err := m.Target.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if that1 == nil {
+		if this == nil {
+			return nil
 		}
-		i += n3
+		return fmt.Errorf("that is type *HandshakeResponse but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *HandshakeResponse but is not nil && this == nil")
 	}
-	return i, nil
+	if !bytes.Equal(this.Challenge, that1.Challenge) {
+		return fmt.Errorf("Challenge this(%v) Not Equal that(%v)", this.Challenge, that1.Challenge)
+	}
+	if !bytes.Equal(this.EphemeralKey, that1.EphemeralKey) {
+		return fmt.Errorf("EphemeralKey this(%v) Not Equal that(%v)", this.EphemeralKey, that1.EphemeralKey)
+	}
+	if this.Encrypted != that1.Encrypted {
+		return fmt.Errorf("Encrypted this(%v) Not Equal that(%v)", this.Encrypted, that1.Encrypted)
+	}
+	return nil
 }
+func (this *HandshakeResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *LookupNodeResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	that1, ok := that.(*HandshakeResponse)
+	if !ok {
+		that2, ok := that.(HandshakeResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return dAtA[:n], nil
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Challenge, that1.Challenge) {
+		return false
+	}
+	if !bytes.Equal(this.EphemeralKey, that1.EphemeralKey) {
+		return false
+	}
+	if this.Encrypted != that1.Encrypted {
+		return false
+	}
+	return true
 }
+func (this *Ack) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (m *LookupNodeResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Peers) > 0 {
-		for _, msg := range m.Peers {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintStream(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
+	that1, ok := that.(*Ack)
+	if !ok {
+		that2, ok := that.(Ack)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Ack")
 		}
 	}
-	return i, nil
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Ack but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Ack but is not nil && this == nil")
+	}
+	if this.Nonce != that1.Nonce {
+		return fmt.Errorf("Nonce this(%v) Not Equal that(%v)", this.Nonce, that1.Nonce)
+	}
+	return nil
 }
+func (this *Ack) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *Bytes) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	that1, ok := that.(*Ack)
+	if !ok {
+		that2, ok := that.(Ack)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return dAtA[:n], nil
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Nonce != that1.Nonce {
+		return false
+	}
+	return true
 }
+func (this *Goodbye) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (m *Bytes) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Data) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(len(m.Data)))
-		i += copy(dAtA[i:], m.Data)
+	that1, ok := that.(*Goodbye)
+	if !ok {
+		that2, ok := that.(Goodbye)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Goodbye")
+		}
 	}
-	return i, nil
-}
-
-func encodeVarintStream(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Goodbye but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Goodbye but is not nil && this == nil")
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return nil
 }
-func (m *ID) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.PublicKey)
-	if l > 0 {
-		n += 1 + l + sovStream(uint64(l))
+func (this *Goodbye) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	l = len(m.Address)
-	if l > 0 {
-		n += 1 + l + sovStream(uint64(l))
+
+	that1, ok := that.(*Goodbye)
+	if !ok {
+		that2, ok := that.(Goodbye)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return n
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
 }
-
-func (m *Message) Size() (n int) {
-	var l int
-	_ = l
-	if m.Message != nil {
-		l = m.Message.Size()
-		n += 1 + l + sovStream(uint64(l))
+func (this *Error) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
 	}
-	if m.Sender != nil {
-		l = m.Sender.Size()
-		n += 1 + l + sovStream(uint64(l))
+
+	that1, ok := that.(*Error)
+	if !ok {
+		that2, ok := that.(Error)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Error")
+		}
 	}
-	l = len(m.Signature)
-	if l > 0 {
-		n += 1 + l + sovStream(uint64(l))
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Error but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Error but is not nil && this == nil")
 	}
-	if m.RequestNonce != 0 {
-		n += 1 + sovStream(uint64(m.RequestNonce))
+	if this.Message != that1.Message {
+		return fmt.Errorf("Message this(%v) Not Equal that(%v)", this.Message, that1.Message)
 	}
-	if m.MessageNonce != 0 {
-		n += 1 + sovStream(uint64(m.MessageNonce))
+	if this.Code != that1.Code {
+		return fmt.Errorf("Code this(%v) Not Equal that(%v)", this.Code, that1.Code)
 	}
-	if m.ReplyFlag {
-		n += 2
+	if !this.Details.Equal(that1.Details) {
+		return fmt.Errorf("Details this(%v) Not Equal that(%v)", this.Details, that1.Details)
 	}
-	return n
+	return nil
 }
+func (this *Error) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *Ping) Size() (n int) {
-	var l int
-	_ = l
-	return n
+	that1, ok := that.(*Error)
+	if !ok {
+		that2, ok := that.(Error)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Message != that1.Message {
+		return false
+	}
+	if this.Code != that1.Code {
+		return false
+	}
+	if !this.Details.Equal(that1.Details) {
+		return false
+	}
+	return true
 }
+func (this *StreamOpen) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (m *Pong) Size() (n int) {
-	var l int
-	_ = l
-	return n
+	that1, ok := that.(*StreamOpen)
+	if !ok {
+		that2, ok := that.(StreamOpen)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *StreamOpen")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *StreamOpen but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *StreamOpen but is not nil && this == nil")
+	}
+	if !this.Message.Equal(that1.Message) {
+		return fmt.Errorf("Message this(%v) Not Equal that(%v)", this.Message, that1.Message)
+	}
+	return nil
 }
+func (this *StreamOpen) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *LookupNodeRequest) Size() (n int) {
-	var l int
-	_ = l
-	if m.Target != nil {
-		l = m.Target.Size()
-		n += 1 + l + sovStream(uint64(l))
+	that1, ok := that.(*StreamOpen)
+	if !ok {
+		that2, ok := that.(StreamOpen)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return n
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Message.Equal(that1.Message) {
+		return false
+	}
+	return true
 }
+func (this *StreamEnd) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (m *LookupNodeResponse) Size() (n int) {
-	var l int
-	_ = l
-	if len(m.Peers) > 0 {
-		for _, e := range m.Peers {
-			l = e.Size()
-			n += 1 + l + sovStream(uint64(l))
+	that1, ok := that.(*StreamEnd)
+	if !ok {
+		that2, ok := that.(StreamEnd)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *StreamEnd")
 		}
 	}
-	return n
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *StreamEnd but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *StreamEnd but is not nil && this == nil")
+	}
+	return nil
 }
+func (this *StreamEnd) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *Bytes) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Data)
-	if l > 0 {
-		n += 1 + l + sovStream(uint64(l))
+	that1, ok := that.(*StreamEnd)
+	if !ok {
+		that2, ok := that.(StreamEnd)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return n
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
 }
+func (this *StreamCancel) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func sovStream(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+	that1, ok := that.(*StreamCancel)
+	if !ok {
+		that2, ok := that.(StreamCancel)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *StreamCancel")
 		}
 	}
-	return n
-}
-func sozStream(x uint64) (n int) {
-	return sovStream(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *ID) String() string {
-	if this == nil {
-		return "nil"
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *StreamCancel but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *StreamCancel but is not nil && this == nil")
 	}
-	s := strings.Join([]string{`&ID{`,
-		`PublicKey:` + fmt.Sprintf("%v", this.PublicKey) + `,`,
-		`Address:` + fmt.Sprintf("%v", this.Address) + `,`,
-		`}`,
-	}, "")
-	return s
+	return nil
 }
-func (this *Message) String() string {
-	if this == nil {
-		return "nil"
+func (this *StreamCancel) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := strings.Join([]string{`&Message{`,
-		`Message:` + strings.Replace(fmt.Sprintf("%v", this.Message), "Any", "types.Any", 1) + `,`,
-		`Sender:` + strings.Replace(fmt.Sprintf("%v", this.Sender), "ID", "ID", 1) + `,`,
-		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
-		`RequestNonce:` + fmt.Sprintf("%v", this.RequestNonce) + `,`,
-		`MessageNonce:` + fmt.Sprintf("%v", this.MessageNonce) + `,`,
-		`ReplyFlag:` + fmt.Sprintf("%v", this.ReplyFlag) + `,`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*StreamCancel)
+	if !ok {
+		that2, ok := that.(StreamCancel)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
 }
-func (this *Ping) String() string {
+func (this *Chunk) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*Chunk)
+	if !ok {
+		that2, ok := that.(Chunk)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Chunk")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Chunk but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Chunk but is not nil && this == nil")
+	}
+	if this.Transfer != that1.Transfer {
+		return fmt.Errorf("Transfer this(%v) Not Equal that(%v)", this.Transfer, that1.Transfer)
+	}
+	if this.Index != that1.Index {
+		return fmt.Errorf("Index this(%v) Not Equal that(%v)", this.Index, that1.Index)
+	}
+	if this.Total != that1.Total {
+		return fmt.Errorf("Total this(%v) Not Equal that(%v)", this.Total, that1.Total)
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return fmt.Errorf("Data this(%v) Not Equal that(%v)", this.Data, that1.Data)
+	}
+	if this.Length != that1.Length {
+		return fmt.Errorf("Length this(%v) Not Equal that(%v)", this.Length, that1.Length)
+	}
+	if !bytes.Equal(this.Hash, that1.Hash) {
+		return fmt.Errorf("Hash this(%v) Not Equal that(%v)", this.Hash, that1.Hash)
+	}
+	return nil
+}
+func (this *Chunk) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Chunk)
+	if !ok {
+		that2, ok := that.(Chunk)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Transfer != that1.Transfer {
+		return false
+	}
+	if this.Index != that1.Index {
+		return false
+	}
+	if this.Total != that1.Total {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	if this.Length != that1.Length {
+		return false
+	}
+	if !bytes.Equal(this.Hash, that1.Hash) {
+		return false
+	}
+	return true
+}
+func (this *StreamFrame) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*StreamFrame)
+	if !ok {
+		that2, ok := that.(StreamFrame)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *StreamFrame")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *StreamFrame but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *StreamFrame but is not nil && this == nil")
+	}
+	if this.Stream != that1.Stream {
+		return fmt.Errorf("Stream this(%v) Not Equal that(%v)", this.Stream, that1.Stream)
+	}
+	if this.Initiator != that1.Initiator {
+		return fmt.Errorf("Initiator this(%v) Not Equal that(%v)", this.Initiator, that1.Initiator)
+	}
+	if this.Open != that1.Open {
+		return fmt.Errorf("Open this(%v) Not Equal that(%v)", this.Open, that1.Open)
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return fmt.Errorf("Data this(%v) Not Equal that(%v)", this.Data, that1.Data)
+	}
+	if this.Window != that1.Window {
+		return fmt.Errorf("Window this(%v) Not Equal that(%v)", this.Window, that1.Window)
+	}
+	if this.Fin != that1.Fin {
+		return fmt.Errorf("Fin this(%v) Not Equal that(%v)", this.Fin, that1.Fin)
+	}
+	if this.Abort != that1.Abort {
+		return fmt.Errorf("Abort this(%v) Not Equal that(%v)", this.Abort, that1.Abort)
+	}
+	return nil
+}
+func (this *StreamFrame) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*StreamFrame)
+	if !ok {
+		that2, ok := that.(StreamFrame)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Stream != that1.Stream {
+		return false
+	}
+	if this.Initiator != that1.Initiator {
+		return false
+	}
+	if this.Open != that1.Open {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	if this.Window != that1.Window {
+		return false
+	}
+	if this.Fin != that1.Fin {
+		return false
+	}
+	if this.Abort != that1.Abort {
+		return false
+	}
+	return true
+}
+func (this *StreamCredit) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*StreamCredit)
+	if !ok {
+		that2, ok := that.(StreamCredit)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *StreamCredit")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *StreamCredit but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *StreamCredit but is not nil && this == nil")
+	}
+	if this.Count != that1.Count {
+		return fmt.Errorf("Count this(%v) Not Equal that(%v)", this.Count, that1.Count)
+	}
+	return nil
+}
+func (this *StreamCredit) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*StreamCredit)
+	if !ok {
+		that2, ok := that.(StreamCredit)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Count != that1.Count {
+		return false
+	}
+	return true
+}
+func (this *ID) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&Ping{`,
-		`}`,
-	}, "")
-	return s
+	s := make([]string, 0, 7)
+	s = append(s, "&protobuf.ID{")
+	s = append(s, "PublicKey: "+fmt.Sprintf("%#v", this.PublicKey)+",\n")
+	s = append(s, "Address: "+fmt.Sprintf("%#v", this.Address)+",\n")
+	s = append(s, "Addresses: "+fmt.Sprintf("%#v", this.Addresses)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-func (this *Pong) String() string {
+func (this *Message) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&Pong{`,
-		`}`,
-	}, "")
-	return s
+	s := make([]string, 0, 11)
+	s = append(s, "&protobuf.Message{")
+	if this.Message != nil {
+		s = append(s, "Message: "+fmt.Sprintf("%#v", this.Message)+",\n")
+	}
+	if this.Sender != nil {
+		s = append(s, "Sender: "+fmt.Sprintf("%#v", this.Sender)+",\n")
+	}
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	s = append(s, "RequestNonce: "+fmt.Sprintf("%#v", this.RequestNonce)+",\n")
+	s = append(s, "MessageNonce: "+fmt.Sprintf("%#v", this.MessageNonce)+",\n")
+	s = append(s, "ReplyFlag: "+fmt.Sprintf("%#v", this.ReplyFlag)+",\n")
+	s = append(s, "Mac: "+fmt.Sprintf("%#v", this.Mac)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-func (this *LookupNodeRequest) String() string {
+func (this *Ping) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&LookupNodeRequest{`,
-		`Target:` + strings.Replace(fmt.Sprintf("%v", this.Target), "ID", "ID", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	s := make([]string, 0, 4)
+	s = append(s, "&protobuf.Ping{")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-func (this *LookupNodeResponse) String() string {
+func (this *Pong) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&LookupNodeResponse{`,
-		`Peers:` + strings.Replace(fmt.Sprintf("%v", this.Peers), "ID", "ID", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	s := make([]string, 0, 4)
+	s = append(s, "&protobuf.Pong{")
+	s = append(s, "}")
+	return strings.Join(s, "")
 }
-func (this *Bytes) String() string {
+func (this *LookupNodeRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.LookupNodeRequest{")
+	if this.Target != nil {
+		s = append(s, "Target: "+fmt.Sprintf("%#v", this.Target)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LookupNodeResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&Bytes{`,
-		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringStream(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.LookupNodeResponse{")
+	if this.Peers != nil {
+		s = append(s, "Peers: "+fmt.Sprintf("%#v", this.Peers)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Bytes) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.Bytes{")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *HandshakeRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&protobuf.HandshakeRequest{")
+	s = append(s, "Challenge: "+fmt.Sprintf("%#v", this.Challenge)+",\n")
+	s = append(s, "Compressors: "+fmt.Sprintf("%#v", this.Compressors)+",\n")
+	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
+	s = append(s, "Codec: "+fmt.Sprintf("%#v", this.Codec)+",\n")
+	s = append(s, "Features: "+fmt.Sprintf("%#v", this.Features)+",\n")
+	s = append(s, "Probe: "+fmt.Sprintf("%#v", this.Probe)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *HandshakeResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&protobuf.HandshakeResponse{")
+	s = append(s, "Challenge: "+fmt.Sprintf("%#v", this.Challenge)+",\n")
+	s = append(s, "EphemeralKey: "+fmt.Sprintf("%#v", this.EphemeralKey)+",\n")
+	s = append(s, "Encrypted: "+fmt.Sprintf("%#v", this.Encrypted)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Ack) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.Ack{")
+	s = append(s, "Nonce: "+fmt.Sprintf("%#v", this.Nonce)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Goodbye) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&protobuf.Goodbye{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Error) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&protobuf.Error{")
+	s = append(s, "Message: "+fmt.Sprintf("%#v", this.Message)+",\n")
+	s = append(s, "Code: "+fmt.Sprintf("%#v", this.Code)+",\n")
+	if this.Details != nil {
+		s = append(s, "Details: "+fmt.Sprintf("%#v", this.Details)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamOpen) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.StreamOpen{")
+	if this.Message != nil {
+		s = append(s, "Message: "+fmt.Sprintf("%#v", this.Message)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamEnd) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&protobuf.StreamEnd{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamCancel) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&protobuf.StreamCancel{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Chunk) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&protobuf.Chunk{")
+	s = append(s, "Transfer: "+fmt.Sprintf("%#v", this.Transfer)+",\n")
+	s = append(s, "Index: "+fmt.Sprintf("%#v", this.Index)+",\n")
+	s = append(s, "Total: "+fmt.Sprintf("%#v", this.Total)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "Length: "+fmt.Sprintf("%#v", this.Length)+",\n")
+	s = append(s, "Hash: "+fmt.Sprintf("%#v", this.Hash)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamFrame) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 11)
+	s = append(s, "&protobuf.StreamFrame{")
+	s = append(s, "Stream: "+fmt.Sprintf("%#v", this.Stream)+",\n")
+	s = append(s, "Initiator: "+fmt.Sprintf("%#v", this.Initiator)+",\n")
+	s = append(s, "Open: "+fmt.Sprintf("%#v", this.Open)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "Window: "+fmt.Sprintf("%#v", this.Window)+",\n")
+	s = append(s, "Fin: "+fmt.Sprintf("%#v", this.Fin)+",\n")
+	s = append(s, "Abort: "+fmt.Sprintf("%#v", this.Abort)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamCredit) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.StreamCredit{")
+	s = append(s, "Count: "+fmt.Sprintf("%#v", this.Count)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringStream(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *ID) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ID) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.PublicKey) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.PublicKey)))
+		i += copy(dAtA[i:], m.PublicKey)
+	}
+	if len(m.Address) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Address)))
+		i += copy(dAtA[i:], m.Address)
+	}
+	if len(m.Addresses) > 0 {
+		for _, s := range m.Addresses {
+			dAtA[i] = 0x1a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	return i, nil
+}
+
+func (m *Message) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Message) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Message != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Message.Size()))
+		n1, err := m.Message.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.Sender != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Sender.Size()))
+		n2, err := m.Sender.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if len(m.Signature) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Signature)))
+		i += copy(dAtA[i:], m.Signature)
+	}
+	if m.RequestNonce != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.RequestNonce))
+	}
+	if m.MessageNonce != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.MessageNonce))
+	}
+	if m.ReplyFlag {
+		dAtA[i] = 0x30
+		i++
+		if m.ReplyFlag {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.Mac) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Mac)))
+		i += copy(dAtA[i:], m.Mac)
+	}
+	return i, nil
+}
+
+func (m *Ping) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Ping) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
+func (m *Pong) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Pong) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
+func (m *LookupNodeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LookupNodeRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Target != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Target.Size()))
+		n3, err := m.Target.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	return i, nil
+}
+
+func (m *LookupNodeResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LookupNodeResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Peers) > 0 {
+		for _, msg := range m.Peers {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintStream(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *Bytes) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Bytes) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
+	}
+	return i, nil
+}
+
+func (m *HandshakeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HandshakeRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Challenge) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Challenge)))
+		i += copy(dAtA[i:], m.Challenge)
+	}
+	if len(m.Compressors) > 0 {
+		for _, s := range m.Compressors {
+			dAtA[i] = 0x12
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.Version != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Version))
+	}
+	if len(m.Codec) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Codec)))
+		i += copy(dAtA[i:], m.Codec)
+	}
+	if len(m.Features) > 0 {
+		for _, s := range m.Features {
+			dAtA[i] = 0x2a
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	if m.Probe {
+		dAtA[i] = 0x30
+		i++
+		if m.Probe {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *HandshakeResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HandshakeResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Challenge) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Challenge)))
+		i += copy(dAtA[i:], m.Challenge)
+	}
+	if len(m.EphemeralKey) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.EphemeralKey)))
+		i += copy(dAtA[i:], m.EphemeralKey)
+	}
+	if m.Encrypted {
+		dAtA[i] = 0x18
+		i++
+		if m.Encrypted {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *Ack) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Ack) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Nonce != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Nonce))
+	}
+	return i, nil
+}
+
+func (m *Goodbye) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Goodbye) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
+func (m *Error) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Error) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Message) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Message)))
+		i += copy(dAtA[i:], m.Message)
+	}
+	if m.Code != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Code))
+	}
+	if m.Details != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Details.Size()))
+		n4, err := m.Details.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	return i, nil
+}
+
+func (m *StreamOpen) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamOpen) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Message != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Message.Size()))
+		n5, err := m.Message.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	return i, nil
+}
+
+func (m *StreamEnd) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamEnd) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
+func (m *StreamCancel) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamCancel) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	return i, nil
+}
+
+func (m *Chunk) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Chunk) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Transfer != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Transfer))
+	}
+	if m.Index != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Index))
+	}
+	if m.Total != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Total))
+	}
+	if len(m.Data) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
+	}
+	if m.Length != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Length))
+	}
+	if len(m.Hash) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Hash)))
+		i += copy(dAtA[i:], m.Hash)
+	}
+	return i, nil
+}
+
+func (m *StreamFrame) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamFrame) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Stream != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Stream))
+	}
+	if m.Initiator {
+		dAtA[i] = 0x10
+		i++
+		if m.Initiator {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Open {
+		dAtA[i] = 0x18
+		i++
+		if m.Open {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if len(m.Data) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
+	}
+	if m.Window != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Window))
+	}
+	if m.Fin {
+		dAtA[i] = 0x30
+		i++
+		if m.Fin {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Abort {
+		dAtA[i] = 0x38
+		i++
+		if m.Abort {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *StreamCredit) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamCredit) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Count != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Count))
+	}
+	return i, nil
+}
+
+func encodeVarintStream(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *ID) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.PublicKey)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if len(m.Addresses) > 0 {
+		for _, s := range m.Addresses {
+			l = len(s)
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Message) Size() (n int) {
+	var l int
+	_ = l
+	if m.Message != nil {
+		l = m.Message.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Sender != nil {
+		l = m.Sender.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.RequestNonce != 0 {
+		n += 1 + sovStream(uint64(m.RequestNonce))
+	}
+	if m.MessageNonce != 0 {
+		n += 1 + sovStream(uint64(m.MessageNonce))
+	}
+	if m.ReplyFlag {
+		n += 2
+	}
+	l = len(m.Mac)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *Ping) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *Pong) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *LookupNodeRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Target != nil {
+		l = m.Target.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *LookupNodeResponse) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Peers) > 0 {
+		for _, e := range m.Peers {
+			l = e.Size()
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Bytes) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *HandshakeRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Challenge)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if len(m.Compressors) > 0 {
+		for _, s := range m.Compressors {
+			l = len(s)
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	if m.Version != 0 {
+		n += 1 + sovStream(uint64(m.Version))
+	}
+	l = len(m.Codec)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if len(m.Features) > 0 {
+		for _, s := range m.Features {
+			l = len(s)
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	if m.Probe {
+		n += 2
+	}
+	return n
+}
+
+func (m *HandshakeResponse) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Challenge)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.EphemeralKey)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Encrypted {
+		n += 2
+	}
+	return n
+}
+
+func (m *Ack) Size() (n int) {
+	var l int
+	_ = l
+	if m.Nonce != 0 {
+		n += 1 + sovStream(uint64(m.Nonce))
+	}
+	return n
+}
+
+func (m *Goodbye) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *Error) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Code != 0 {
+		n += 1 + sovStream(uint64(m.Code))
+	}
+	if m.Details != nil {
+		l = m.Details.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *StreamOpen) Size() (n int) {
+	var l int
+	_ = l
+	if m.Message != nil {
+		l = m.Message.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *StreamEnd) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *StreamCancel) Size() (n int) {
+	var l int
+	_ = l
+	return n
+}
+
+func (m *Chunk) Size() (n int) {
+	var l int
+	_ = l
+	if m.Transfer != 0 {
+		n += 1 + sovStream(uint64(m.Transfer))
+	}
+	if m.Index != 0 {
+		n += 1 + sovStream(uint64(m.Index))
+	}
+	if m.Total != 0 {
+		n += 1 + sovStream(uint64(m.Total))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Length != 0 {
+		n += 1 + sovStream(uint64(m.Length))
+	}
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *StreamFrame) Size() (n int) {
+	var l int
+	_ = l
+	if m.Stream != 0 {
+		n += 1 + sovStream(uint64(m.Stream))
+	}
+	if m.Initiator {
+		n += 2
+	}
+	if m.Open {
+		n += 2
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Window != 0 {
+		n += 1 + sovStream(uint64(m.Window))
+	}
+	if m.Fin {
+		n += 2
+	}
+	if m.Abort {
+		n += 2
+	}
+	return n
+}
+
+func (m *StreamCredit) Size() (n int) {
+	var l int
+	_ = l
+	if m.Count != 0 {
+		n += 1 + sovStream(uint64(m.Count))
+	}
+	return n
+}
+
+func sovStream(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozStream(x uint64) (n int) {
+	return sovStream(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *ID) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ID{`,
+		`PublicKey:` + fmt.Sprintf("%v", this.PublicKey) + `,`,
+		`Address:` + fmt.Sprintf("%v", this.Address) + `,`,
+		`Addresses:` + fmt.Sprintf("%v", this.Addresses) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Message) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Message{`,
+		`Message:` + strings.Replace(fmt.Sprintf("%v", this.Message), "Any", "types.Any", 1) + `,`,
+		`Sender:` + strings.Replace(fmt.Sprintf("%v", this.Sender), "ID", "ID", 1) + `,`,
+		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
+		`RequestNonce:` + fmt.Sprintf("%v", this.RequestNonce) + `,`,
+		`MessageNonce:` + fmt.Sprintf("%v", this.MessageNonce) + `,`,
+		`ReplyFlag:` + fmt.Sprintf("%v", this.ReplyFlag) + `,`,
+		`Mac:` + fmt.Sprintf("%v", this.Mac) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Ping) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Ping{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Pong) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Pong{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LookupNodeRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LookupNodeRequest{`,
+		`Target:` + strings.Replace(fmt.Sprintf("%v", this.Target), "ID", "ID", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LookupNodeResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LookupNodeResponse{`,
+		`Peers:` + strings.Replace(fmt.Sprintf("%v", this.Peers), "ID", "ID", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Bytes) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Bytes{`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HandshakeRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&HandshakeRequest{`,
+		`Challenge:` + fmt.Sprintf("%v", this.Challenge) + `,`,
+		`Compressors:` + fmt.Sprintf("%v", this.Compressors) + `,`,
+		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
+		`Codec:` + fmt.Sprintf("%v", this.Codec) + `,`,
+		`Features:` + fmt.Sprintf("%v", this.Features) + `,`,
+		`Probe:` + fmt.Sprintf("%v", this.Probe) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HandshakeResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&HandshakeResponse{`,
+		`Challenge:` + fmt.Sprintf("%v", this.Challenge) + `,`,
+		`EphemeralKey:` + fmt.Sprintf("%v", this.EphemeralKey) + `,`,
+		`Encrypted:` + fmt.Sprintf("%v", this.Encrypted) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Ack) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Ack{`,
+		`Nonce:` + fmt.Sprintf("%v", this.Nonce) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Goodbye) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Goodbye{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Error) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Error{`,
+		`Message:` + fmt.Sprintf("%v", this.Message) + `,`,
+		`Code:` + fmt.Sprintf("%v", this.Code) + `,`,
+		`Details:` + strings.Replace(fmt.Sprintf("%v", this.Details), "Any", "types.Any", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StreamOpen) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamOpen{`,
+		`Message:` + strings.Replace(fmt.Sprintf("%v", this.Message), "Any", "types.Any", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StreamEnd) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamEnd{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StreamCancel) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamCancel{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Chunk) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Chunk{`,
+		`Transfer:` + fmt.Sprintf("%v", this.Transfer) + `,`,
+		`Index:` + fmt.Sprintf("%v", this.Index) + `,`,
+		`Total:` + fmt.Sprintf("%v", this.Total) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`Length:` + fmt.Sprintf("%v", this.Length) + `,`,
+		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StreamFrame) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamFrame{`,
+		`Stream:` + fmt.Sprintf("%v", this.Stream) + `,`,
+		`Initiator:` + fmt.Sprintf("%v", this.Initiator) + `,`,
+		`Open:` + fmt.Sprintf("%v", this.Open) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`Window:` + fmt.Sprintf("%v", this.Window) + `,`,
+		`Fin:` + fmt.Sprintf("%v", this.Fin) + `,`,
+		`Abort:` + fmt.Sprintf("%v", this.Abort) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StreamCredit) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamCredit{`,
+		`Count:` + fmt.Sprintf("%v", this.Count) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringStream(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *ID) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ID: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ID: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PublicKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PublicKey = append(m.PublicKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.PublicKey == nil {
+				m.PublicKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Addresses", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Addresses = append(m.Addresses, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Message) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Message: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Message: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Message == nil {
+				m.Message = &types.Any{}
+			}
+			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Sender == nil {
+				m.Sender = &ID{}
+			}
+			if err := m.Sender.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestNonce", wireType)
+			}
+			m.RequestNonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RequestNonce |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageNonce", wireType)
+			}
+			m.MessageNonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MessageNonce |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplyFlag", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ReplyFlag = bool(v != 0)
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Mac", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Mac = append(m.Mac[:0], dAtA[iNdEx:postIndex]...)
+			if m.Mac == nil {
+				m.Mac = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Ping) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Ping: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Ping: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Pong) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Pong: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Pong: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LookupNodeRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LookupNodeRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LookupNodeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Target == nil {
+				m.Target = &ID{}
+			}
+			if err := m.Target.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LookupNodeResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LookupNodeResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LookupNodeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Peers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Peers = append(m.Peers, &ID{})
+			if err := m.Peers[len(m.Peers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Bytes) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Bytes: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Bytes: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HandshakeRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HandshakeRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HandshakeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Challenge", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Challenge = append(m.Challenge[:0], dAtA[iNdEx:postIndex]...)
+			if m.Challenge == nil {
+				m.Challenge = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compressors", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Compressors = append(m.Compressors, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
+			}
+			m.Version = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Version |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Codec", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Codec = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Features", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Features = append(m.Features, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Probe", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Probe = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HandshakeResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HandshakeResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HandshakeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Challenge", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Challenge = append(m.Challenge[:0], dAtA[iNdEx:postIndex]...)
+			if m.Challenge == nil {
+				m.Challenge = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EphemeralKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EphemeralKey = append(m.EphemeralKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.EphemeralKey == nil {
+				m.EphemeralKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Encrypted", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Encrypted = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *ID) Unmarshal(dAtA []byte) error {
+func (m *Ack) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1314,17 +4682,17 @@ func (m *ID) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ID: wiretype end group for non-group")
+			return fmt.Errorf("proto: Ack: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ID: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Ack: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PublicKey", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
 			}
-			var byteLen int
+			m.Nonce = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1334,52 +4702,61 @@ func (m *ID) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.Nonce |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthStream
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.PublicKey = append(m.PublicKey[:0], dAtA[iNdEx:postIndex]...)
-			if m.PublicKey == nil {
-				m.PublicKey = []byte{}
-			}
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowStream
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthStream
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Goodbye) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
 			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
+			if iNdEx >= l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Address = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Goodbye: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Goodbye: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStream(dAtA[iNdEx:])
@@ -1401,7 +4778,7 @@ func (m *ID) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Message) Unmarshal(dAtA []byte) error {
+func (m *Error) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1424,17 +4801,17 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Message: wiretype end group for non-group")
+			return fmt.Errorf("proto: Error: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Message: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Error: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1444,30 +4821,26 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthStream
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Message == nil {
-				m.Message = &types.Any{}
-			}
-			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Message = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Code", wireType)
 			}
-			var msglen int
+			m.Code = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1477,30 +4850,16 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Code |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthStream
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			if m.Sender == nil {
-				m.Sender = &ID{}
-			}
-			if err := m.Sender.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Details", wireType)
 			}
-			var byteLen int
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1510,66 +4869,80 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthStream
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
-			if m.Signature == nil {
-				m.Signature = []byte{}
+			if m.Details == nil {
+				m.Details = &types.Any{}
+			}
+			if err := m.Details.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
 			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RequestNonce", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			m.RequestNonce = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowStream
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.RequestNonce |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
 			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MessageNonce", wireType)
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
-			m.MessageNonce = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowStream
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.MessageNonce |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StreamOpen) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
 			}
-		case 6:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ReplyFlag", wireType)
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
 			}
-			var v int
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamOpen: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamOpen: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1579,12 +4952,25 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.ReplyFlag = bool(v != 0)
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Message == nil {
+				m.Message = &types.Any{}
+			}
+			if err := m.Message.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStream(dAtA[iNdEx:])
@@ -1606,7 +4992,7 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Ping) Unmarshal(dAtA []byte) error {
+func (m *StreamEnd) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1629,10 +5015,10 @@ func (m *Ping) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Ping: wiretype end group for non-group")
+			return fmt.Errorf("proto: StreamEnd: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Ping: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StreamEnd: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		default:
@@ -1656,7 +5042,7 @@ func (m *Ping) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Pong) Unmarshal(dAtA []byte) error {
+func (m *StreamCancel) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1679,10 +5065,10 @@ func (m *Pong) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Pong: wiretype end group for non-group")
+			return fmt.Errorf("proto: StreamCancel: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Pong: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StreamCancel: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		default:
@@ -1706,7 +5092,7 @@ func (m *Pong) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LookupNodeRequest) Unmarshal(dAtA []byte) error {
+func (m *Chunk) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1729,17 +5115,74 @@ func (m *LookupNodeRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LookupNodeRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: Chunk: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LookupNodeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Chunk: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Transfer", wireType)
+			}
+			m.Transfer = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Transfer |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Total", wireType)
+			}
+			m.Total = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Total |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1749,23 +5192,71 @@ func (m *LookupNodeRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthStream
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Target == nil {
-				m.Target = &ID{}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
 			}
-			if err := m.Target.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Length", wireType)
+			}
+			m.Length = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Length |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -1789,7 +5280,7 @@ func (m *LookupNodeRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LookupNodeResponse) Unmarshal(dAtA []byte) error {
+func (m *StreamFrame) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1812,17 +5303,76 @@ func (m *LookupNodeResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LookupNodeResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: StreamFrame: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LookupNodeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StreamFrame: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stream", wireType)
+			}
+			m.Stream = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Stream |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Initiator", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Initiator = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Open", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Open = bool(v != 0)
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Peers", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1832,23 +5382,82 @@ func (m *LookupNodeResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthStream
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Peers = append(m.Peers, &ID{})
-			if err := m.Peers[len(m.Peers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Window", wireType)
+			}
+			m.Window = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Window |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fin", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Fin = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Abort", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Abort = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStream(dAtA[iNdEx:])
@@ -1870,7 +5479,7 @@ func (m *LookupNodeResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Bytes) Unmarshal(dAtA []byte) error {
+func (m *StreamCredit) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1893,17 +5502,17 @@ func (m *Bytes) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Bytes: wiretype end group for non-group")
+			return fmt.Errorf("proto: StreamCredit: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Bytes: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: StreamCredit: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
 			}
-			var byteLen int
+			m.Count = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1913,23 +5522,11 @@ func (m *Bytes) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.Count |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthStream
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
-			if m.Data == nil {
-				m.Data = []byte{}
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStream(dAtA[iNdEx:])
@@ -2056,35 +5653,59 @@ var (
 	ErrIntOverflowStream   = fmt.Errorf("proto: integer overflow")
 )
 
-func init() { proto.RegisterFile("protobuf/stream.proto", fileDescriptor_stream_943b2a05a2172ca3) }
-
-var fileDescriptor_stream_943b2a05a2172ca3 = []byte{
-	// 420 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0x4d, 0x6e, 0xd4, 0x30,
-	0x14, 0xc7, 0xeb, 0x76, 0x3e, 0x3a, 0xaf, 0xc3, 0x02, 0x0b, 0x50, 0x28, 0xd4, 0x8a, 0x0c, 0x8b,
-	0x59, 0xa5, 0x52, 0xd9, 0xc0, 0x82, 0x05, 0xa3, 0x0a, 0xa9, 0x7c, 0x8c, 0x46, 0xb9, 0xc0, 0xc8,
-	0xd3, 0xbc, 0x5a, 0x51, 0x53, 0x3b, 0xd8, 0xce, 0x22, 0x3b, 0x8e, 0xc0, 0x31, 0x38, 0x0a, 0x4b,
-	0x96, 0x2c, 0x3b, 0x41, 0x62, 0xcd, 0x11, 0x50, 0x6c, 0x4f, 0x07, 0xc1, 0x2a, 0xef, 0xff, 0x7b,
-	0x3f, 0xdb, 0xcf, 0x31, 0x3c, 0xac, 0x8d, 0x76, 0x7a, 0xdd, 0x5c, 0x9d, 0x5a, 0x67, 0x50, 0xdc,
-	0x64, 0x3e, 0xd3, 0xc3, 0x2d, 0x3e, 0x7e, 0x2c, 0xb5, 0x96, 0x15, 0x9e, 0xde, 0x79, 0x42, 0xb5,
-	0x41, 0x3a, 0xe6, 0x52, 0x4b, 0xbd, 0x6b, 0xf4, 0xc9, 0x07, 0x5f, 0x05, 0x87, 0xbf, 0x86, 0xfd,
-	0x8b, 0x73, 0x7a, 0x02, 0x50, 0x37, 0xeb, 0xaa, 0xbc, 0x5c, 0x5d, 0x63, 0x9b, 0x90, 0x94, 0xcc,
-	0xa6, 0xf9, 0x24, 0x90, 0xf7, 0xd8, 0xd2, 0x04, 0xc6, 0xa2, 0x28, 0x0c, 0x5a, 0x9b, 0xec, 0xa7,
-	0x64, 0x36, 0xc9, 0xb7, 0x91, 0xff, 0x22, 0x30, 0xfe, 0x88, 0xd6, 0x0a, 0x89, 0x34, 0x83, 0xf1,
-	0x4d, 0x28, 0xfd, 0x0e, 0x47, 0x67, 0x0f, 0xb2, 0x30, 0x5b, 0xb6, 0x1d, 0x21, 0x7b, 0xa3, 0xda,
-	0x7c, 0x2b, 0xd1, 0xe7, 0x30, 0xb2, 0xa8, 0x0a, 0x34, 0x7e, 0xd3, 0xa3, 0xb3, 0xe9, 0xce, 0xbb,
-	0x38, 0xcf, 0x63, 0x8f, 0x3e, 0x85, 0x89, 0x2d, 0xa5, 0x12, 0xae, 0x31, 0x98, 0x1c, 0x84, 0xc9,
-	0xee, 0x00, 0x7d, 0x06, 0xf7, 0x0c, 0x7e, 0x6a, 0xd0, 0xba, 0x95, 0xd2, 0xea, 0x12, 0x93, 0x41,
-	0x4a, 0x66, 0x83, 0x7c, 0x1a, 0xe1, 0xa2, 0x67, 0xbd, 0x14, 0xcf, 0x8c, 0xd2, 0x30, 0x48, 0x11,
-	0x06, 0xe9, 0x04, 0xc0, 0x60, 0x5d, 0xb5, 0xab, 0xab, 0x4a, 0xc8, 0x64, 0x94, 0x92, 0xd9, 0x61,
-	0x3e, 0xf1, 0xe4, 0x6d, 0x25, 0x24, 0x1f, 0xc1, 0x60, 0x59, 0xaa, 0xf0, 0xd5, 0x4a, 0xf2, 0x57,
-	0x70, 0xff, 0x83, 0xd6, 0xd7, 0x4d, 0xbd, 0xd0, 0x05, 0xe6, 0xe1, 0xb4, 0xfe, 0x46, 0x4e, 0x18,
-	0x89, 0x2e, 0xfe, 0x80, 0x7f, 0x6e, 0x14, 0x7a, 0xfc, 0x25, 0xd0, 0xbf, 0x97, 0xda, 0x5a, 0x2b,
-	0x8b, 0x94, 0xc3, 0xb0, 0x46, 0x34, 0x36, 0x21, 0xe9, 0xc1, 0x7f, 0x4b, 0x43, 0x8b, 0x3f, 0x81,
-	0xe1, 0xbc, 0x75, 0x68, 0x29, 0x85, 0x41, 0x21, 0x9c, 0x88, 0x2f, 0xe5, 0xeb, 0xf9, 0xbb, 0x1f,
-	0x1b, 0xb6, 0x77, 0xbb, 0x61, 0xe4, 0xf7, 0x86, 0x91, 0xcf, 0x1d, 0x23, 0x5f, 0x3b, 0x46, 0xbe,
-	0x75, 0x8c, 0x7c, 0xef, 0x18, 0xb9, 0xed, 0x18, 0xf9, 0xf2, 0x93, 0xed, 0xc1, 0x23, 0x6d, 0x64,
-	0x56, 0xa3, 0xa9, 0x4a, 0x95, 0x29, 0x5d, 0xda, 0xf8, 0x38, 0x73, 0x58, 0xf4, 0x61, 0xd9, 0xd7,
-	0x4b, 0xb2, 0x1e, 0x79, 0xf8, 0xe2, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4f, 0x88, 0x51, 0x33,
-	0x7e, 0x02, 0x00, 0x00,
+func init() { proto.RegisterFile("protobuf/stream.proto", fileDescriptor_stream_f9fcdbd4ca5a461e) }
+
+var fileDescriptor_stream_f9fcdbd4ca5a461e = []byte{
+	// 809 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x4d, 0x8f, 0x1b, 0x35,
+	0x18, 0xae, 0x37, 0x9f, 0xf3, 0x26, 0x41, 0xad, 0x55, 0xaa, 0x61, 0xbb, 0x1d, 0x45, 0x6e, 0x0f,
+	0x39, 0xa5, 0x52, 0xb9, 0x80, 0xc4, 0xa5, 0xdb, 0x0f, 0x28, 0x1f, 0xcb, 0xca, 0x1c, 0x39, 0xac,
+	0x9c, 0x99, 0x37, 0x93, 0x51, 0x26, 0xf6, 0x60, 0x3b, 0x94, 0xdc, 0xf8, 0x07, 0xf0, 0x33, 0x90,
+	0xb8, 0xf2, 0x23, 0x38, 0x72, 0xe4, 0xd8, 0x0d, 0x7f, 0x80, 0x23, 0x47, 0xe4, 0x8f, 0x64, 0x56,
+	0x02, 0x2a, 0x71, 0x9a, 0xf7, 0x79, 0xfc, 0x7a, 0xfc, 0xd8, 0xcf, 0x63, 0xc3, 0xbb, 0x8d, 0x56,
+	0x56, 0x2d, 0xb6, 0xcb, 0xc7, 0xc6, 0x6a, 0x14, 0x9b, 0xb9, 0xc7, 0x74, 0x78, 0xa0, 0x4f, 0xdf,
+	0x2b, 0x95, 0x2a, 0x6b, 0x7c, 0x7c, 0xec, 0x13, 0x72, 0x17, 0x9a, 0x4e, 0x59, 0xa9, 0x4a, 0xd5,
+	0x0e, 0x38, 0xe4, 0x81, 0xaf, 0x42, 0x0f, 0xfb, 0x1a, 0x4e, 0x5e, 0x3d, 0xa7, 0x0f, 0x00, 0x9a,
+	0xed, 0xa2, 0xae, 0xf2, 0xab, 0x35, 0xee, 0x52, 0x32, 0x25, 0xb3, 0x31, 0x4f, 0x02, 0xf3, 0x19,
+	0xee, 0x68, 0x0a, 0x03, 0x51, 0x14, 0x1a, 0x8d, 0x49, 0x4f, 0xa6, 0x64, 0x96, 0xf0, 0x03, 0xa4,
+	0x67, 0x90, 0xc4, 0x12, 0x4d, 0xda, 0x99, 0x76, 0x66, 0x09, 0x6f, 0x09, 0xf6, 0x17, 0x81, 0xc1,
+	0x17, 0x68, 0x8c, 0x28, 0x91, 0xce, 0x61, 0xb0, 0x09, 0xa5, 0xff, 0xff, 0xe8, 0xc9, 0xdd, 0x79,
+	0x50, 0x3e, 0x3f, 0x08, 0x9c, 0x3f, 0x95, 0x3b, 0x7e, 0x68, 0xa2, 0x8f, 0xa0, 0x6f, 0x50, 0x16,
+	0xa8, 0xfd, 0x92, 0xa3, 0x27, 0xe3, 0xb6, 0xef, 0xd5, 0x73, 0x1e, 0xc7, 0xdc, 0xfa, 0xa6, 0x2a,
+	0xa5, 0xb0, 0x5b, 0x8d, 0x69, 0x27, 0xe8, 0x3e, 0x12, 0xf4, 0x21, 0x4c, 0x34, 0x7e, 0xb3, 0x45,
+	0x63, 0xaf, 0xa4, 0x92, 0x39, 0xa6, 0xdd, 0x29, 0x99, 0x75, 0xf9, 0x38, 0x92, 0x17, 0x8e, 0x73,
+	0x4d, 0x71, 0xcd, 0xd8, 0xd4, 0x0b, 0x4d, 0x91, 0x0c, 0x4d, 0x0f, 0x00, 0x34, 0x36, 0xf5, 0xee,
+	0x6a, 0x59, 0x8b, 0x32, 0xed, 0x4f, 0xc9, 0x6c, 0xc8, 0x13, 0xcf, 0xbc, 0xac, 0x45, 0x49, 0x6f,
+	0x43, 0x67, 0x23, 0xf2, 0x74, 0xe0, 0x05, 0xb8, 0x92, 0xf5, 0xa1, 0x7b, 0x59, 0xc9, 0xd2, 0x7f,
+	0x95, 0x2c, 0xd9, 0x87, 0x70, 0xe7, 0x73, 0xa5, 0xd6, 0xdb, 0xe6, 0x42, 0x15, 0xc8, 0xc3, 0xfa,
+	0x6e, 0x8f, 0x56, 0xe8, 0x12, 0x6d, 0x4a, 0xfe, 0x6d, 0x8f, 0x61, 0x8c, 0x7d, 0x00, 0xf4, 0xe6,
+	0x54, 0xd3, 0x28, 0x69, 0x90, 0x32, 0xe8, 0x35, 0x88, 0xda, 0xa4, 0x64, 0xda, 0xf9, 0xc7, 0xd4,
+	0x30, 0xc4, 0xee, 0x43, 0xef, 0x7c, 0x67, 0xd1, 0x50, 0x0a, 0xdd, 0x42, 0x58, 0x11, 0x9d, 0xf5,
+	0x35, 0xfb, 0x85, 0xc0, 0xed, 0x4f, 0x84, 0x2c, 0xcc, 0x4a, 0xac, 0x8f, 0x8a, 0xce, 0x20, 0xc9,
+	0x57, 0xa2, 0xae, 0x51, 0x46, 0x9f, 0xc6, 0xbc, 0x25, 0xe8, 0x14, 0x46, 0xb9, 0xda, 0x34, 0xce,
+	0x5d, 0xa5, 0x5d, 0x16, 0x9c, 0xdf, 0x37, 0x29, 0x97, 0x94, 0x6f, 0x51, 0x9b, 0x4a, 0x49, 0xef,
+	0xc6, 0x84, 0x1f, 0x20, 0xbd, 0x0b, 0xbd, 0x5c, 0x15, 0x98, 0x7b, 0x0f, 0x12, 0x1e, 0x00, 0x3d,
+	0x85, 0xe1, 0x12, 0xbd, 0x59, 0x26, 0xed, 0xf9, 0xdf, 0x1d, 0xb1, 0x9b, 0xd1, 0x68, 0xb5, 0xc0,
+	0x78, 0xdc, 0x01, 0x30, 0x0b, 0x77, 0x6e, 0xa8, 0x8e, 0x87, 0xf1, 0x76, 0xd9, 0x0f, 0x61, 0x82,
+	0xcd, 0x0a, 0x37, 0xa8, 0x45, 0xed, 0x03, 0x7e, 0xe2, 0x3b, 0xc6, 0x47, 0xd2, 0x65, 0xfc, 0x0c,
+	0x12, 0x94, 0xb9, 0xde, 0x35, 0x16, 0x0b, 0xaf, 0x7d, 0xc8, 0x5b, 0x82, 0xdd, 0x87, 0xce, 0xd3,
+	0x7c, 0xed, 0x24, 0x85, 0x8c, 0x10, 0x9f, 0x91, 0x00, 0x58, 0x02, 0x83, 0x8f, 0x95, 0x2a, 0x16,
+	0x3b, 0x64, 0x08, 0xbd, 0x17, 0x5a, 0x2b, 0xed, 0x0e, 0xe2, 0x66, 0xdc, 0x93, 0x36, 0xd8, 0x14,
+	0xba, 0x6e, 0xef, 0x5e, 0xc4, 0x84, 0xfb, 0xda, 0x5d, 0x8e, 0x02, 0xad, 0xa8, 0x6a, 0x93, 0x76,
+	0xde, 0x76, 0x39, 0x62, 0x13, 0xfb, 0x08, 0xe0, 0x2b, 0xff, 0x1c, 0x7c, 0xd9, 0xa0, 0xfc, 0xbf,
+	0x57, 0x8b, 0x8d, 0x20, 0x09, 0xb3, 0x5f, 0xc8, 0x82, 0xbd, 0x03, 0xe3, 0x00, 0x9e, 0x09, 0x99,
+	0x63, 0xcd, 0x7e, 0x20, 0xd0, 0x7b, 0xb6, 0xda, 0xca, 0xb5, 0xf3, 0xc6, 0x6a, 0x21, 0xcd, 0x12,
+	0x75, 0xdc, 0xef, 0x11, 0xbb, 0x83, 0xa8, 0x64, 0x81, 0xdf, 0xc5, 0x5d, 0x04, 0xe0, 0x58, 0xab,
+	0xac, 0xa8, 0xa3, 0xf7, 0x01, 0x1c, 0xc3, 0xd7, 0x6d, 0xc3, 0x47, 0xef, 0x41, 0xdf, 0x79, 0x63,
+	0x57, 0xf1, 0xb6, 0x45, 0xe4, 0x7a, 0x57, 0xc2, 0xac, 0xbc, 0xe5, 0x63, 0xee, 0x6b, 0xf6, 0x33,
+	0x81, 0x51, 0x90, 0xf8, 0x52, 0x8b, 0x0d, 0xba, 0xb9, 0xe1, 0x2d, 0xf4, 0xaa, 0x26, 0x3c, 0x22,
+	0xe7, 0x60, 0x25, 0x2b, 0x5b, 0x09, 0xab, 0xc2, 0xa3, 0x31, 0xe4, 0x2d, 0xe1, 0xfe, 0xac, 0x1a,
+	0x94, 0xd1, 0x5a, 0x5f, 0xff, 0x97, 0xb2, 0xd7, 0x95, 0x2c, 0xd4, 0x6b, 0xaf, 0x6c, 0xc2, 0x23,
+	0x72, 0x57, 0x7c, 0x59, 0xc9, 0x98, 0x45, 0x57, 0xba, 0xdd, 0x8a, 0x85, 0xd2, 0xd6, 0x5f, 0xfb,
+	0x21, 0x0f, 0x80, 0x3d, 0x3a, 0x9e, 0xa7, 0xc6, 0xa2, 0xb2, 0x21, 0xf7, 0x5b, 0x69, 0xa3, 0xd8,
+	0x00, 0xce, 0x3f, 0xfd, 0xfd, 0x3a, 0xbb, 0xf5, 0xe6, 0x3a, 0x23, 0x7f, 0x5e, 0x67, 0xe4, 0xfb,
+	0x7d, 0x46, 0x7e, 0xda, 0x67, 0xe4, 0xd7, 0x7d, 0x46, 0x7e, 0xdb, 0x67, 0xe4, 0xcd, 0x3e, 0x23,
+	0x3f, 0xfe, 0x91, 0xdd, 0x82, 0x7b, 0x4a, 0x97, 0xf3, 0x06, 0x75, 0x5d, 0xc9, 0xb9, 0x54, 0x95,
+	0x89, 0x86, 0x9e, 0xc3, 0x85, 0x03, 0x97, 0xae, 0xbe, 0x24, 0x8b, 0xbe, 0x27, 0xdf, 0xff, 0x7b,
+	0x00, 0x04, 0x01, 0x32, 0x75, 0x2b, 0x06, 0x00, 0x00,
 }
@@ -35,8 +35,12 @@ func generateProtos(dir string) error {
 				fmt.Sprintf("-I=%s", filepath.Join(goPath, "src", "github.com", "gogo", "protobuf", "protobuf")),
 				fmt.Sprintf("--proto_path=%s", filepath.Join(goPath, "src", "github.com")),
 				"--gogofaster_out=Mgoogle/protobuf/any.proto=github.com/gogo/protobuf/types,Mgoogle/protobuf/duration.proto=github.com/gogo/protobuf/types,Mgoogle/protobuf/struct.proto=github.com/gogo/protobuf/types,Mgoogle/protobuf/timestamp.proto=github.com/gogo/protobuf/types,Mgoogle/protobuf/wrappers.proto=github.com/gogo/protobuf/types:.",
-				path,
 			}
+			// generate typed rpc stubs should protoc-gen-noise be installed
+			if _, err := exec.LookPath("protoc-gen-noise"); err == nil {
+				args = append(args, "--noise_out=.")
+			}
+			args = append(args, path)
 			cmd := exec.Command("protoc", args...)
 			err = cmd.Run()
 			if err != nil {
@@ -1,14 +1,24 @@
 package test
 
 import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/crypto/blake2b"
 	"github.com/perlin-network/noise/crypto/ed25519"
 	"github.com/perlin-network/noise/network"
 	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/rpc"
 	"github.com/perlin-network/noise/peer"
 	"github.com/perlin-network/noise/test/protobuf"
 	"github.com/pkg/errors"
@@ -16,15 +26,16 @@ import (
 
 type env struct {
 	name      string
-	network   string // network type (e.g., tcp or kcp)
+	network   string // network type (e.g., tcp, kcp or memory)
 	hash      crypto.HashPolicy
 	signature crypto.SignaturePolicy
 }
 
 var (
-	kcpEnv  = env{name: "kcp-blake2b-ed25519", network: "kcp", hash: blake2b.New(), signature: ed25519.New()}
-	tcpEnv  = env{name: "tcp-blake2b-ed25519", network: "tcp", hash: blake2b.New(), signature: ed25519.New()}
-	allEnvs = []env{kcpEnv, tcpEnv}
+	kcpEnv    = env{name: "kcp-blake2b-ed25519", network: "kcp", hash: blake2b.New(), signature: ed25519.New()}
+	tcpEnv    = env{name: "tcp-blake2b-ed25519", network: "tcp", hash: blake2b.New(), signature: ed25519.New()}
+	memoryEnv = env{name: "memory-blake2b-ed25519", network: "memory", hash: blake2b.New(), signature: ed25519.New()}
+	allEnvs   = []env{kcpEnv, tcpEnv, memoryEnv}
 )
 
 type test struct {
@@ -41,7 +52,7 @@ func (te *test) startBoostrap(numNodes int, plugins ...network.PluginInterface)
 	for i := 0; i < numNodes; i++ {
 		builder := network.NewBuilderWithOptions(te.builderOptions...)
 		builder.SetKeys(te.e.signature.RandomKeyPair())
-		builder.SetAddress(network.FormatAddress(te.e.network, "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.SetAddress(network.FormatAddress(te.e.network, "localhost", te.port()))
 
 		builder.AddPlugin(new(discovery.Plugin))
 		builder.AddPlugin(new(MailBoxPlugin))
@@ -85,6 +96,18 @@ func (te *test) startBoostrap(numNodes int, plugins ...network.PluginInterface)
 	}
 }
 
+// memoryPort is the last port assigned to nodes listening in-memory.
+var memoryPort uint32 = 30000
+
+// port returns a port for a node to listen on. Nodes listening in-memory are assigned ports
+// without binding sockets.
+func (te *test) port() uint16 {
+	if te.e.network == "memory" {
+		return uint16(atomic.AddUint32(&memoryPort, 1))
+	}
+	return uint16(network.GetRandomUnusedPort())
+}
+
 func (te *test) tearDown() {
 	for _, node := range te.nodes {
 		node.Close()
@@ -146,6 +169,14 @@ func testNodeConnect(t *testing.T, e env) {
 	if len(peers) != numNodes-1 {
 		t.Errorf("len(peers) = %d, want %d", len(peers), numNodes-1)
 	}
+
+	client, err := te.bootstrapNode.Client(te.nodes[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if features := client.Features(); features == nil || features.Version != network.ProtocolVersion || !features.Has(network.FeatureStreams) {
+		t.Errorf("Features() = %+v, want protocol version %d with streams", features, network.ProtocolVersion)
+	}
 }
 
 func TestNodeBroadcast(t *testing.T) {
@@ -184,6 +215,646 @@ func testNodeBroadcast(t *testing.T, e env) {
 	}
 }
 
+func TestNodeBroadcastEncrypted(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skipf("skipping %s in short mode", t.Name())
+	}
+
+	for _, e := range allEnvs {
+		testNodeBroadcastWithOptions(t, e, network.EncryptSessions(true))
+	}
+}
+
+func TestNodeBroadcastSessionMAC(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skipf("skipping %s in short mode", t.Name())
+	}
+
+	for _, e := range allEnvs {
+		testNodeBroadcastWithOptions(t, e, network.MessageAuthentication(network.AuthSessionMAC))
+	}
+}
+
+func TestNodeBroadcastOrdered(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skipf("skipping %s in short mode", t.Name())
+	}
+
+	for _, e := range allEnvs {
+		testNodeBroadcastWithOptions(t, e, network.OrderedDelivery(true))
+	}
+}
+
+func TestNodeBroadcastCompactCodec(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skipf("skipping %s in short mode", t.Name())
+	}
+
+	codec := network.NewCompactCodec()
+	if err := codec.Register(network.MinCompactTypeID, new(protobuf.TestMessage)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range allEnvs {
+		testNodeBroadcastWithOptions(t, e, network.WireCodec(codec))
+	}
+}
+
+func testNodeBroadcastWithOptions(t *testing.T, e env, opts ...network.BuilderOption) {
+	te := newTest(t, e, append([]network.BuilderOption{network.WriteTimeout(1 * time.Second)}, opts...)...)
+	numNodes := 3
+	te.startBoostrap(numNodes)
+	defer te.tearDown()
+
+	expected := "test message"
+	te.bootstrapNode.Broadcast(&protobuf.TestMessage{Message: expected})
+
+	for i, node := range te.nodes {
+		select {
+		case received := <-te.getMailbox(node).RecvMailbox:
+			if received.Message != expected {
+				t.Errorf("Expected message %s to be received by node %d but got %v\n", expected, i+1, received.Message)
+			}
+		case <-time.After(1 * time.Second):
+			t.Errorf("Timed out attempting to receive message from Node 0.\n")
+		}
+	}
+}
+
+func TestNodeSendWindow(t *testing.T) {
+	t.Parallel()
+	if testing.Short() {
+		t.Skipf("skipping %s in short mode", t.Name())
+	}
+
+	for _, e := range allEnvs {
+		testNodeSendWindow(t, e)
+	}
+}
+
+func testNodeSendWindow(t *testing.T, e env) {
+	te := newTest(t, e, network.WriteTimeout(1*time.Second), network.SendWindowSize(2))
+	te.startBoostrap(2)
+	defer te.tearDown()
+
+	client, err := te.bootstrapNode.Client(te.nodes[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Far more messages than the window allows are only delivered should acknowledgements flow back.
+	numMessages := 50
+	errs := make(chan error, 1)
+
+	go func() {
+		for i := 0; i < numMessages; i++ {
+			if err := client.Tell(&protobuf.TestMessage{Message: "test message"}); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < numMessages; i++ {
+		select {
+		case <-te.getMailbox(te.nodes[0]).RecvMailbox:
+		case err := <-errs:
+			t.Fatalf("Tell() = %v, expected <nil>", err)
+		case <-time.After(1 * time.Second):
+			t.Fatalf("Timed out after receiving %d of %d messages.", i, numMessages)
+		}
+	}
+}
+
+func TestNodeLargeMessage(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range allEnvs {
+		testNodeLargeMessage(t, e)
+	}
+}
+
+func testNodeLargeMessage(t *testing.T, e env) {
+	te := newTest(t, e)
+	te.startBoostrap(2)
+	defer te.tearDown()
+
+	client, err := te.bootstrapNode.Client(te.nodes[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Larger than what may be sent in a single frame.
+	expected := strings.Repeat("large message ", 5e5)
+
+	go func() {
+		if err := client.Tell(&protobuf.TestMessage{Message: expected}); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	select {
+	case received := <-te.getMailbox(te.nodes[0]).RecvMailbox:
+		if received.Message != expected {
+			t.Errorf("received message of %d bytes, expected %d bytes", len(received.Message), len(expected))
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for large message")
+	}
+}
+
+func TestNodeCompression(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range allEnvs {
+		testNodeCompression(t, e)
+	}
+}
+
+func testNodeCompression(t *testing.T, e env) {
+	te := newTest(t, e, network.Compression(network.NewFlateCompressor(flate.BestSpeed)))
+	te.startBoostrap(2)
+	defer te.tearDown()
+
+	client, err := te.bootstrapNode.Client(te.nodes[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Repeat("compressible message ", 1e4)
+
+	if err := client.Tell(&protobuf.TestMessage{Message: expected}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case received := <-te.getMailbox(te.nodes[0]).RecvMailbox:
+		if received.Message != expected {
+			t.Errorf("received message of %d bytes, expected %d bytes", len(received.Message), len(expected))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for message")
+	}
+
+	stats := client.CompressionStats()
+	if stats.BytesSent < uint64(len(expected)) {
+		t.Errorf("expected at least %d bytes to be counted as sent, got %d", len(expected), stats.BytesSent)
+	}
+	if ratio := stats.SendRatio(); ratio > 0.5 {
+		t.Errorf("expected message to be sent compressed, got a compression ratio of %f", ratio)
+	}
+}
+
+func TestNodeStreams(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range allEnvs {
+		testNodeStreams(t, e)
+	}
+}
+
+func testNodeStreams(t *testing.T, e env) {
+	te := newTest(t, e)
+	te.startBoostrap(2)
+	defer te.tearDown()
+
+	// Echo back all bytes written to streams opened with the node.
+	go func() {
+		for {
+			stream, err := te.nodes[0].AcceptStream()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer stream.Close()
+				io.Copy(stream, stream)
+			}()
+		}
+	}()
+
+	client, err := te.bootstrapNode.Client(te.nodes[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write more than the window of a stream to several streams at once.
+	const numStreams, size = 3, 1 << 20
+
+	errs := make(chan error, numStreams)
+
+	for i := 0; i < numStreams; i++ {
+		go func(i int) {
+			stream, err := client.OpenStream()
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer stream.Close()
+
+			stream.SetDeadline(time.Now().Add(10 * time.Second))
+
+			expected := bytes.Repeat([]byte{byte(i)}, size)
+
+			go func() {
+				stream.Write(expected)
+				stream.CloseWrite()
+			}()
+
+			received, err := ioutil.ReadAll(stream)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if !bytes.Equal(received, expected) {
+				errs <- errors.Errorf("stream %d echoed back %d bytes, expected %d bytes", stream.ID(), len(received), len(expected))
+				return
+			}
+			errs <- nil
+		}(i)
+	}
+
+	for i := 0; i < numStreams; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+
+	// Reset streams fail on both ends.
+	stream, err := client.OpenStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := stream.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Write([]byte("test")); err != network.ErrStreamReset {
+		t.Errorf("Write() = %v, expected %v", err, network.ErrStreamReset)
+	}
+}
+
+func TestNodeRequestContext(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range allEnvs {
+		testNodeRequestContext(t, e)
+	}
+}
+
+func testNodeRequestContext(t *testing.T, e env) {
+	te := newTest(t, e)
+	te.startBoostrap(2)
+	defer te.tearDown()
+
+	client, err := te.bootstrapNode.Client(te.nodes[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nobody replies to test messages, so the request only returns once cancelled.
+	go func() {
+		<-te.getMailbox(te.nodes[0]).RecvMailbox
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := client.RequestContext(ctx, &rpc.Request{Message: &protobuf.TestMessage{Message: "test message"}}); err == nil {
+		t.Fatal("RequestContext() = <nil>, expected an error")
+	}
+
+	if elapsed := time.Since(start); elapsed > 1*time.Second {
+		t.Errorf("RequestContext() took %s to return after its context was done", elapsed)
+	}
+}
+
+func TestNodeRPCService(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range allEnvs {
+		testNodeRPCService(t, e)
+	}
+}
+
+func testNodeRPCService(t *testing.T, e env) {
+	service := rpc.NewService()
+	err := service.Handle(func(ctx context.Context, req *protobuf.TestMessage) (*protobuf.TestMessage, error) {
+		switch req.Message {
+		case "fail":
+			return nil, errors.New("test failure")
+		case "reject":
+			return nil, &rpc.Error{Code: rpc.CodeUser, Message: "test rejection", Details: req}
+		case "nil error":
+			var err *rpc.Error
+			return &protobuf.TestMessage{Message: "no error"}, err
+		}
+		return &protobuf.TestMessage{Message: "echo: " + req.Message}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	te := newTest(t, e, network.RPCService(service))
+	te.startBoostrap(2)
+	defer te.tearDown()
+
+	client, err := te.bootstrapNode.Client(te.nodes[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := client.Request(&rpc.Request{Message: &protobuf.TestMessage{Message: "test message"}, Timeout: 3 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply, ok := res.(*protobuf.TestMessage); !ok || reply.Message != "echo: test message" {
+		t.Errorf("Request() = %v, expected an echoed test message", res)
+	}
+
+	res, err = client.Request(&rpc.Request{Message: &protobuf.TestMessage{Message: "nil error"}, Timeout: 3 * time.Second})
+	if err != nil {
+		t.Fatalf("expected a handler returning a nil *rpc.Error to succeed: %v", err)
+	}
+	if reply, ok := res.(*protobuf.TestMessage); !ok || reply.Message != "no error" {
+		t.Errorf("Request() = %v, expected the handler's response", res)
+	}
+
+	_, err = client.Request(&rpc.Request{Message: &protobuf.TestMessage{Message: "fail"}, Timeout: 3 * time.Second})
+	if rpcErr, ok := err.(*rpc.Error); !ok || rpcErr.Code != rpc.CodeUnknown || rpcErr.Message != "test failure" {
+		t.Errorf("Request() = %v, expected the handler's error", err)
+	}
+
+	_, err = client.Request(&rpc.Request{Message: &protobuf.TestMessage{Message: "reject"}, Timeout: 3 * time.Second})
+	rpcErr, ok := err.(*rpc.Error)
+	if !ok || rpcErr.Code != rpc.CodeUser || rpcErr.Message != "test rejection" {
+		t.Fatalf("Request() = %v, expected the handler's error", err)
+	}
+	if details, ok := rpcErr.Details.(*protobuf.TestMessage); !ok || details.Message != "reject" {
+		t.Errorf("Request() error details = %v, expected the rejected request", rpcErr.Details)
+	}
+}
+
+func TestNodeShutdown(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range allEnvs {
+		testNodeShutdown(t, e)
+	}
+}
+
+func testNodeShutdown(t *testing.T, e env) {
+	handling := make(chan struct{})
+
+	service := rpc.NewService()
+	err := service.Handle(func(ctx context.Context, req *protobuf.TestMessage) (*protobuf.TestMessage, error) {
+		close(handling)
+		time.Sleep(300 * time.Millisecond)
+		return &protobuf.TestMessage{Message: "echo: " + req.Message}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	te := newTest(t, e, network.RPCService(service))
+	te.startBoostrap(2)
+	defer te.tearDown()
+
+	client, err := te.bootstrapNode.Client(te.nodes[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		res proto.Message
+		err error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		res, err := client.Request(&rpc.Request{Message: &protobuf.TestMessage{Message: "test message"}, Timeout: 3 * time.Second})
+		done <- result{res, err}
+	}()
+
+	<-handling
+
+	// The request being handled is replied to before the node shuts down.
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if err := te.nodes[0].Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() = %v, expected no error", err)
+	}
+
+	r := <-done
+	if r.err != nil {
+		t.Fatalf("Request() = %v, expected the request to be handled before shutting down", r.err)
+	}
+	if reply, ok := r.res.(*protobuf.TestMessage); !ok || reply.Message != "echo: test message" {
+		t.Errorf("Request() = %v, expected an echoed test message", r.res)
+	}
+
+	if !client.ShuttingDown() {
+		t.Error("ShuttingDown() = false, expected the peer to have said goodbye")
+	}
+	if err := client.Tell(&protobuf.TestMessage{Message: "too late"}); err != network.ErrPeerShuttingDown {
+		t.Errorf("Tell() = %v, expected %v", err, network.ErrPeerShuttingDown)
+	}
+}
+
+func TestNodeRequestStream(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range allEnvs {
+		testNodeRequestStream(t, e)
+	}
+}
+
+func testNodeRequestStream(t *testing.T, e env) {
+	const numPages = 20
+
+	service := rpc.NewService()
+	err := service.HandleStream(func(ctx context.Context, req *protobuf.TestMessage, stream rpc.Stream) error {
+		switch req.Message {
+		case "page":
+			for i := 0; i < numPages; i++ {
+				if err := stream.Send(&protobuf.TestMessage{Message: fmt.Sprintf("page %d", i)}); err != nil {
+					return err
+				}
+			}
+		case "echo":
+			for {
+				msg, err := stream.Recv()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				if err := stream.Send(msg); err != nil {
+					return err
+				}
+			}
+		default:
+			return rpc.Errorf(rpc.CodeInvalidRequest, "unknown stream %q", req.Message)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	te := newTest(t, e, network.RPCService(service))
+	te.startBoostrap(2)
+	defer te.tearDown()
+
+	client, err := te.bootstrapNode.Client(te.nodes[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Streams which are not read from hold up no other stream.
+	unread, err := client.OpenRequestStream(ctx, &protobuf.TestMessage{Message: "page"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unread.Close()
+
+	// Page through results streamed by the peer.
+	stream, err := client.OpenRequestStream(ctx, &protobuf.TestMessage{Message: "page"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	for i := 0; ; i++ {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			if i != numPages {
+				t.Errorf("expected %d pages, got %d", numPages, i)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := fmt.Sprintf("page %d", i); res.(*protobuf.TestMessage).Message != expected {
+			t.Fatalf("Recv() = %v, expected %s", res, expected)
+		}
+	}
+
+	// Exchange messages both ways.
+	echo, err := client.OpenRequestStream(ctx, &protobuf.TestMessage{Message: "echo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer echo.Close()
+
+	for i := 0; i < 5; i++ {
+		expected := fmt.Sprintf("echo %d", i)
+		if err := echo.Send(&protobuf.TestMessage{Message: expected}); err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := echo.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.(*protobuf.TestMessage).Message != expected {
+			t.Fatalf("Recv() = %v, expected %s", res, expected)
+		}
+	}
+
+	if err := echo.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := echo.Recv(); err != io.EOF {
+		t.Errorf("Recv() = %v, expected %v", err, io.EOF)
+	}
+
+	// Streams failed by the peer end with its error.
+	failed, err := client.OpenRequestStream(ctx, &protobuf.TestMessage{Message: "unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer failed.Close()
+
+	if _, err := failed.Recv(); !isRPCError(err, rpc.CodeInvalidRequest) {
+		t.Errorf("Recv() = %v, expected the handler's error", err)
+	}
+}
+
+func isRPCError(err error, code uint32) bool {
+	rpcErr, ok := err.(*rpc.Error)
+	return ok && rpcErr.Code == code
+}
+
+// rejectPlugin fails all test messages it receives with an *rpc.Error.
+type rejectPlugin struct {
+	*network.Plugin
+}
+
+func (p *rejectPlugin) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.TestMessage); ok {
+		return errors.Wrap(rpc.Errorf(rpc.CodeInvalidRequest, "test messages are rejected"), "failed to handle message")
+	}
+	return nil
+}
+
+// failPlugin fails all test messages it receives with an error other than an *rpc.Error.
+type failPlugin struct {
+	*network.Plugin
+}
+
+func (p *failPlugin) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.TestMessage); ok {
+		return errors.New("failed to handle message")
+	}
+	return nil
+}
+
+func TestNodeRequestPluginError(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range allEnvs {
+		testNodeRequestPluginError(t, e, new(rejectPlugin), rpc.CodeInvalidRequest)
+		testNodeRequestPluginError(t, e, new(failPlugin), rpc.CodeUnknown)
+	}
+}
+
+func testNodeRequestPluginError(t *testing.T, e env, plugin network.PluginInterface, code uint32) {
+	te := newTest(t, e)
+	te.startBoostrap(2, plugin)
+	defer te.tearDown()
+
+	client, err := te.bootstrapNode.Client(te.nodes[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		<-te.getMailbox(te.nodes[0]).RecvMailbox
+	}()
+
+	start := time.Now()
+	_, err = client.Request(&rpc.Request{Message: &protobuf.TestMessage{Message: "test message"}, Timeout: 10 * time.Second})
+	if !isRPCError(err, code) {
+		t.Errorf("Request() = %v, expected the plugin's error with code %d", err, code)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Request() took %s to return the plugin's error", elapsed)
+	}
+}
+
 /*
 FIXME(jack0): something wrong with the sending, might be related to other PR
 func TestNodeBroadcastByIDs(t *testing.T) {